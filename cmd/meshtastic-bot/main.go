@@ -35,17 +35,35 @@ func main() {
 		log.Fatalf("Failed to start bot: %v", err)
 	}
 
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if discordBot.IsHealthy() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Service Unavailable"))
+		}
+	})
+
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if discordBot.IsReady() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("OK"))
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Service Unavailable"))
+		}
+	})
+
+	if cfg.WebhookSecret != "" {
+		mux.Handle("/github/webhook", discordBot.WebhookHandler(ctx, cfg.WebhookSecret))
+		log.Println("Receiving GitHub webhooks on /github/webhook")
+	}
+
 	healthServer := &http.Server{
-		Addr: fmt.Sprintf(":%s", cfg.HealthCheckPort),
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if discordBot.IsHealthy() {
-				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("OK"))
-			} else {
-				w.WriteHeader(http.StatusServiceUnavailable)
-				w.Write([]byte("Service Unavailable"))
-			}
-		}),
+		Addr:    fmt.Sprintf(":%s", cfg.HealthCheckPort),
+		Handler: mux,
 	}
 
 	go func() {
@@ -62,18 +80,21 @@ func main() {
 
 	<-stop
 	log.Println("Shutdown signal received...")
-	cancel()
 
-	// Shutdown health check server
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Stop the bot, giving issues being created time to finish. Docker
+	// kills the container 10 seconds after asking it to stop.
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 8*time.Second)
 	defer shutdownCancel()
-	if err := healthServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Health check server shutdown error: %v", err)
+	if err := discordBot.Stop(shutdownCtx); err != nil {
+		log.Printf("Error during shutdown: %v", err)
 	}
+	cancel()
 
-	// Stop the bot
-	if err := discordBot.Stop(ctx); err != nil {
-		log.Printf("Error during shutdown: %v", err)
+	// Shutdown health check server
+	healthCtx, healthCancel := context.WithTimeout(context.Background(), time.Second)
+	defer healthCancel()
+	if err := healthServer.Shutdown(healthCtx); err != nil {
+		log.Printf("Health check server shutdown error: %v", err)
 	}
 
 	log.Println("Bot stopped gracefully")
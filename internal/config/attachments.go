@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// Attachment limits used when a modal's attachments block leaves them unset
+const (
+	DefaultMaxAttachments    = 3
+	DefaultMaxAttachmentSize = 8
+)
+
+// DefaultAttachmentTypes are the content types accepted when a modal's
+// attachments block doesn't list any
+var DefaultAttachmentTypes = []string{"image/*", "text/*"}
+
+// AttachmentConfig limits the files a user can add to a report with /attach
+type AttachmentConfig struct {
+	MaxFiles int `yaml:"max_files,omitempty"`
+	// MaxSizeMB is the largest file accepted, in megabytes
+	MaxSizeMB int `yaml:"max_size_mb,omitempty"`
+	// AllowedTypes are content types such as "image/png", or "image/*" for
+	// every image type
+	AllowedTypes []string `yaml:"allowed_types,omitempty"`
+}
+
+// FileLimit returns the number of files a report can have
+func (a *AttachmentConfig) FileLimit() int {
+	if a.MaxFiles > 0 {
+		return a.MaxFiles
+	}
+	return DefaultMaxAttachments
+}
+
+// MaxBytes returns the largest file accepted, in bytes
+func (a *AttachmentConfig) MaxBytes() int {
+	sizeMB := a.MaxSizeMB
+	if sizeMB <= 0 {
+		sizeMB = DefaultMaxAttachmentSize
+	}
+	return sizeMB * 1024 * 1024
+}
+
+// Allows reports whether a file's content type is accepted. Parameters such
+// as "; charset=utf-8" are ignored.
+func (a *AttachmentConfig) Allows(contentType string) bool {
+	contentType, _, _ = strings.Cut(strings.ToLower(contentType), ";")
+	contentType = strings.TrimSpace(contentType)
+	if contentType == "" {
+		return false
+	}
+
+	allowed := a.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = DefaultAttachmentTypes
+	}
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(strings.ToLower(pattern), contentType); matched {
+			return true
+		}
+	}
+	return false
+}
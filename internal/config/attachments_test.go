@@ -0,0 +1,40 @@
+package config
+
+import "testing"
+
+func TestAttachmentConfig_Allows(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      AttachmentConfig
+		contentType string
+		expected    bool
+	}{
+		{"default allows images", AttachmentConfig{}, "image/png", true},
+		{"default allows text with charset", AttachmentConfig{}, "text/plain; charset=utf-8", true},
+		{"default rejects archives", AttachmentConfig{}, "application/zip", false},
+		{"exact type", AttachmentConfig{AllowedTypes: []string{"application/json"}}, "application/json", true},
+		{"wildcard is case insensitive", AttachmentConfig{AllowedTypes: []string{"Image/*"}}, "IMAGE/JPEG", true},
+		{"configured types replace the defaults", AttachmentConfig{AllowedTypes: []string{"application/json"}}, "image/png", false},
+		{"missing content type", AttachmentConfig{}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.config.Allows(tt.contentType); result != tt.expected {
+				t.Errorf("Allows(%q) = %v, want %v", tt.contentType, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAttachmentConfig_Limits(t *testing.T) {
+	defaults := &AttachmentConfig{}
+	if defaults.FileLimit() != DefaultMaxAttachments || defaults.MaxBytes() != DefaultMaxAttachmentSize*1024*1024 {
+		t.Errorf("default limits = %d files, %d bytes", defaults.FileLimit(), defaults.MaxBytes())
+	}
+
+	configured := &AttachmentConfig{MaxFiles: 1, MaxSizeMB: 2}
+	if configured.FileLimit() != 1 || configured.MaxBytes() != 2*1024*1024 {
+		t.Errorf("configured limits = %d files, %d bytes", configured.FileLimit(), configured.MaxBytes())
+	}
+}
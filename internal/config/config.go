@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,6 +15,80 @@ type Config struct {
 	ConfigPath      string
 	FAQPath         string
 	HealthCheckPort string
+	// TemplateRefreshInterval is how often cached issue templates are revalidated
+	TemplateRefreshInterval time.Duration
+	// DataDir is where state that should survive a restart is kept, in memory only when empty
+	DataDir string
+	// SessionTTL is how long an in-progress report is kept without activity
+	SessionTTL time.Duration
+	// DraftRetention is how long an unfinished report is kept as a draft
+	DraftRetention time.Duration
+	// WebhookSecret verifies GitHub webhook deliveries, webhooks are disabled when empty
+	WebhookSecret string
+	// GithubAppID authenticates as a GitHub App instead of with GithubToken,
+	// using the private key in GithubAppPrivateKey or GithubAppPrivateKeyPath
+	GithubAppID             string
+	GithubAppPrivateKey     string
+	GithubAppPrivateKeyPath string
+	// GithubWebURL, GithubAPIURL and GithubRawURL point the bot at GitHub
+	// Enterprise Server or a local stand-in instead of github.com
+	GithubWebURL string
+	GithubAPIURL string
+	GithubRawURL string
+	// GitlabToken and GiteaToken authenticate with the GitLab and Gitea
+	// instances modals file issues in
+	GitlabToken string
+	GiteaToken  string
+	// AttachmentsRepository is the GitHub repository, as owner/repo, that
+	// files from /attach are committed to. They're linked from Discord,
+	// where links expire, when it's empty.
+	AttachmentsRepository string
+}
+
+// TrackerToken returns the access token for a GitLab or Gitea tracker
+func (c *Config) TrackerToken(tracker Tracker) string {
+	switch tracker.Kind {
+	case TrackerGitLab:
+		return c.GitlabToken
+	case TrackerGitea:
+		return c.GiteaToken
+	}
+	return c.GithubToken
+}
+
+// GitHubURLs returns the configured base URLs of the GitHub instance
+func (c *Config) GitHubURLs() GitHubURLs {
+	return GitHubURLs{Web: c.GithubWebURL, API: c.GithubAPIURL, Raw: c.GithubRawURL}.withDefaults()
+}
+
+// AttachmentsRepo returns the repository attached files are committed to,
+// nil when they're linked from Discord
+func (c *Config) AttachmentsRepo() (*Repository, error) {
+	if c.AttachmentsRepository == "" {
+		return nil, nil
+	}
+	repository, err := parseRepository(c.AttachmentsRepository)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", EnvAttachmentsRepository, err)
+	}
+	return repository, nil
+}
+
+// UsesGithubApp reports whether the bot authenticates as a GitHub App
+func (c *Config) UsesGithubApp() bool {
+	return c.GithubAppID != ""
+}
+
+// GithubAppKey returns the PEM-encoded private key of the GitHub App
+func (c *Config) GithubAppKey() ([]byte, error) {
+	if c.GithubAppPrivateKey != "" {
+		return []byte(c.GithubAppPrivateKey), nil
+	}
+	key, err := os.ReadFile(c.GithubAppPrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", EnvGitHubAppPrivateKeyPath, err)
+	}
+	return key, nil
 }
 
 // TemplateURL represents a parsed GitHub issue template URL
@@ -41,16 +117,21 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-// ParseTemplateURL parses and validates a GitHub template URL
+// ParseTemplateURL parses and validates a GitHub template URL on the
+// configured web host, see SetGitHubURLs
 // Example: https://github.com/meshtastic/web/blob/main/.github/ISSUE_TEMPLATE/bug.yml
 func ParseTemplateURL(templateURL string) (*TemplateURL, error) {
 	if templateURL == "" {
 		return nil, fmt.Errorf("template URL cannot be empty")
 	}
 
-	url := strings.TrimPrefix(templateURL, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "github.com/")
+	url := trimScheme(templateURL)
+	webBase := trimScheme(githubURLs.Web) + "/"
+	if strings.HasPrefix(strings.ToLower(url), strings.ToLower(webBase)) {
+		url = url[len(webBase):]
+	} else if url != templateURL {
+		return nil, fmt.Errorf("template URL must be on %s: %s", githubURLs.Web, templateURL)
+	}
 
 	parts := strings.Split(url, "/")
 	if len(parts) < 2 {
@@ -76,7 +157,7 @@ func (t *TemplateURL) Repo() string {
 // IssueAPIURL returns the GitHub API endpoint for creating issues
 // Example: https://api.github.com/repos/meshtastic/web/issues
 func (t *TemplateURL) IssueAPIURL() string {
-	return fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", t.owner, t.repo)
+	return fmt.Sprintf("%s/repos/%s/%s/issues", githubURLs.API, t.owner, t.repo)
 }
 
 // RawURL returns the raw content URL for fetching the template YAML
@@ -84,7 +165,13 @@ func (t *TemplateURL) IssueAPIURL() string {
 func (t *TemplateURL) RawURL() string {
 	// Remove /blob/ from path if present
 	path := strings.Replace(t.path, "blob/", "", 1)
-	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", t.owner, t.repo, path)
+	return fmt.Sprintf("%s/%s/%s/%s", githubURLs.Raw, t.owner, t.repo, path)
+}
+
+// trimScheme removes a leading http:// or https://
+func trimScheme(url string) string {
+	url = strings.TrimPrefix(url, "https://")
+	return strings.TrimPrefix(url, "http://")
 }
 
 // Returns the original URL
@@ -105,3 +105,33 @@ func TestParseTemplateURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTemplateURL_Enterprise(t *testing.T) {
+	SetGitHubURLs(GitHubURLs{
+		Web: "https://ghe.example.com/",
+		API: "https://ghe.example.com/api/v3",
+		Raw: "https://ghe.example.com/raw",
+	})
+	defer SetGitHubURLs(GitHubURLs{})
+
+	result, err := ParseTemplateURL("https://ghe.example.com/meshtastic/web/blob/main/.github/ISSUE_TEMPLATE/bug.yml")
+	if err != nil {
+		t.Fatalf("ParseTemplateURL() unexpected error: %v", err)
+	}
+	if result.Owner() != "meshtastic" || result.Repo() != "web" {
+		t.Errorf("ParseTemplateURL() = %s/%s, want meshtastic/web", result.Owner(), result.Repo())
+	}
+	if want := "https://ghe.example.com/raw/meshtastic/web/main/.github/ISSUE_TEMPLATE/bug.yml"; result.RawURL() != want {
+		t.Errorf("RawURL() = %q, want %q", result.RawURL(), want)
+	}
+	if want := "https://ghe.example.com/api/v3/repos/meshtastic/web/issues"; result.IssueAPIURL() != want {
+		t.Errorf("IssueAPIURL() = %q, want %q", result.IssueAPIURL(), want)
+	}
+	if GitHubWebHost() != "ghe.example.com" {
+		t.Errorf("GitHubWebHost() = %q, want ghe.example.com", GitHubWebHost())
+	}
+
+	if _, err := ParseTemplateURL("https://github.com/meshtastic/web/blob/main/.github/ISSUE_TEMPLATE/bug.yml"); err == nil {
+		t.Error("ParseTemplateURL() expected error for a github.com URL")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,10 +16,30 @@ const (
 	EnvDiscordServerID = "DISCORD_SERVER_ID"
 	EnvDiscordToken    = "DISCORD_TOKEN"
 	EnvGitHubToken     = "GITHUB_TOKEN"
+	EnvWebhookSecret   = "GITHUB_WEBHOOK_SECRET"
+
+	EnvGitHubAppID             = "GITHUB_APP_ID"
+	EnvGitHubAppPrivateKey     = "GITHUB_APP_PRIVATE_KEY"
+	EnvGitHubAppPrivateKeyPath = "GITHUB_APP_PRIVATE_KEY_PATH"
+
+	EnvGitHubWebURL = "GITHUB_WEB_URL"
+	EnvGitHubAPIURL = "GITHUB_API_URL"
+	EnvGitHubRawURL = "GITHUB_RAW_URL"
+
+	EnvGitLabToken = "GITLAB_TOKEN"
+	EnvGiteaToken  = "GITEA_TOKEN"
+
+	EnvAttachmentsRepository = "ATTACHMENTS_REPOSITORY"
+
 	EnvConfigPath      = "CONFIG_PATH"
 	EnvFAQPath         = "FAQ_PATH"
 	EnvHealthCheckPort = "HEALTHCHECK_PORT"
 	EnvEnvironment     = "ENV"
+
+	EnvTemplateRefreshInterval = "TEMPLATE_REFRESH_INTERVAL"
+	EnvDataDir                 = "DATA_DIR"
+	EnvSessionTTL              = "SESSION_TTL"
+	EnvDraftRetention          = "DRAFT_RETENTION"
 )
 
 // Default values
@@ -25,6 +47,10 @@ const (
 	DefaultHealthCheckPort = "8080"
 	DefaultFAQPath         = "faq.yaml"
 	DefaultEnvironment     = "dev"
+
+	DefaultTemplateRefreshInterval = 15 * time.Minute
+	DefaultSessionTTL              = 30 * time.Minute
+	DefaultDraftRetention          = 7 * 24 * time.Hour
 )
 
 // setDefaults initializes the Config with default values
@@ -32,6 +58,12 @@ func setDefaults(cfg *Config) {
 	cfg.HealthCheckPort = DefaultHealthCheckPort
 	cfg.FAQPath = DefaultFAQPath
 	cfg.RemoveCommands = false
+	cfg.TemplateRefreshInterval = DefaultTemplateRefreshInterval
+	cfg.SessionTTL = DefaultSessionTTL
+	cfg.DraftRetention = DefaultDraftRetention
+	cfg.GithubWebURL = DefaultGitHubWebURL
+	cfg.GithubAPIURL = DefaultGitHubAPIURL
+	cfg.GithubRawURL = DefaultGitHubRawURL
 }
 
 // loadFromEnv loads configuration from environment variables
@@ -48,6 +80,21 @@ func loadEnv(cfg *Config) {
 		EnvConfigPath:      &cfg.ConfigPath,
 		EnvFAQPath:         &cfg.FAQPath,
 		EnvHealthCheckPort: &cfg.HealthCheckPort,
+		EnvDataDir:         &cfg.DataDir,
+		EnvWebhookSecret:   &cfg.WebhookSecret,
+
+		EnvGitHubAppID:             &cfg.GithubAppID,
+		EnvGitHubAppPrivateKey:     &cfg.GithubAppPrivateKey,
+		EnvGitHubAppPrivateKeyPath: &cfg.GithubAppPrivateKeyPath,
+
+		EnvGitHubWebURL: &cfg.GithubWebURL,
+		EnvGitHubAPIURL: &cfg.GithubAPIURL,
+		EnvGitHubRawURL: &cfg.GithubRawURL,
+
+		EnvGitLabToken: &cfg.GitlabToken,
+		EnvGiteaToken:  &cfg.GiteaToken,
+
+		EnvAttachmentsRepository: &cfg.AttachmentsRepository,
 	}
 
 	for envVar, field := range envMappings {
@@ -55,6 +102,23 @@ func loadEnv(cfg *Config) {
 			*field = val
 		}
 	}
+
+	durationMappings := map[string]*time.Duration{
+		EnvTemplateRefreshInterval: &cfg.TemplateRefreshInterval,
+		EnvSessionTTL:              &cfg.SessionTTL,
+		EnvDraftRetention:          &cfg.DraftRetention,
+	}
+
+	for envVar, field := range durationMappings {
+		if val := os.Getenv(envVar); val != "" {
+			duration, err := time.ParseDuration(val)
+			if err != nil {
+				log.Printf("Ignoring invalid %s %q: %v", envVar, val, err)
+				continue
+			}
+			*field = duration
+		}
+	}
 }
 
 // loadEnvFile loads the appropriate .env file based on the ENV variable
@@ -84,9 +148,21 @@ func applyFlags(cfg *Config) {
 	flag.StringVar(&cfg.ServerID, "server-id", cfg.ServerID, "Discord server ID")
 	flag.StringVar(&cfg.DiscordToken, "discord-token", cfg.DiscordToken, "Discord bot access token")
 	flag.StringVar(&cfg.GithubToken, "github-token", cfg.GithubToken, "GitHub access token")
+	flag.StringVar(&cfg.GithubAppID, "github-app-id", cfg.GithubAppID, "GitHub App ID, used instead of a GitHub access token")
+	flag.StringVar(&cfg.GithubAppPrivateKeyPath, "github-app-private-key-path", cfg.GithubAppPrivateKeyPath, "Location of the GitHub App's private key")
+	flag.StringVar(&cfg.GithubWebURL, "github-web-url", cfg.GithubWebURL, "Base URL of GitHub's web UI, for GitHub Enterprise Server")
+	flag.StringVar(&cfg.GithubAPIURL, "github-api-url", cfg.GithubAPIURL, "Base URL of GitHub's REST API, for GitHub Enterprise Server")
+	flag.StringVar(&cfg.GithubRawURL, "github-raw-url", cfg.GithubRawURL, "Base URL of GitHub's raw file contents, for GitHub Enterprise Server")
+	flag.StringVar(&cfg.GitlabToken, "gitlab-token", cfg.GitlabToken, "GitLab access token, for modals filing issues on GitLab")
+	flag.StringVar(&cfg.GiteaToken, "gitea-token", cfg.GiteaToken, "Gitea or Forgejo access token, for modals filing issues on Gitea")
 	flag.StringVar(&cfg.ConfigPath, "config-path", cfg.ConfigPath, "Location of modal yaml configuration file")
 	flag.StringVar(&cfg.FAQPath, "faq-path", cfg.FAQPath, "Location of FAQ yaml file")
 	flag.StringVar(&cfg.HealthCheckPort, "healthcheck-port", cfg.HealthCheckPort, "Health check HTTP server port")
+	flag.DurationVar(&cfg.TemplateRefreshInterval, "template-refresh-interval", cfg.TemplateRefreshInterval, "How often cached issue templates are revalidated")
+	flag.StringVar(&cfg.DataDir, "data-dir", cfg.DataDir, "Directory for state that survives restarts (in memory only when empty)")
+	flag.DurationVar(&cfg.SessionTTL, "session-ttl", cfg.SessionTTL, "How long an in-progress report is kept without activity")
+	flag.DurationVar(&cfg.DraftRetention, "draft-retention", cfg.DraftRetention, "How long unfinished reports are kept as drafts")
+	flag.StringVar(&cfg.WebhookSecret, "github-webhook-secret", cfg.WebhookSecret, "Secret GitHub webhooks are signed with (webhooks are disabled when empty)")
 	flag.BoolVar(&cfg.RemoveCommands, "remove-commands", cfg.RemoveCommands, "Remove Discord commands on shutdown")
 	flag.Parse()
 }
@@ -96,7 +172,6 @@ func (c *Config) Validate() error {
 	requiredFields := map[string]string{
 		EnvDiscordToken:    c.DiscordToken,
 		EnvDiscordServerID: c.ServerID,
-		EnvGitHubToken:     c.GithubToken,
 		EnvConfigPath:      c.ConfigPath,
 	}
 
@@ -106,6 +181,22 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.UsesGithubApp() {
+		if _, err := strconv.ParseInt(c.GithubAppID, 10, 64); err != nil {
+			return fmt.Errorf("%s must be a number: %s", EnvGitHubAppID, c.GithubAppID)
+		}
+		if c.GithubAppPrivateKey == "" && c.GithubAppPrivateKeyPath == "" {
+			return fmt.Errorf("%s or %s is required with %s", EnvGitHubAppPrivateKey, EnvGitHubAppPrivateKeyPath, EnvGitHubAppID)
+		}
+	}
+
+	if err := c.GitHubURLs().validate(); err != nil {
+		return err
+	}
+	if _, err := c.AttachmentsRepo(); err != nil {
+		return err
+	}
+
 	// Validate the config path exists and is a file
 	if info, err := os.Stat(c.ConfigPath); err != nil {
 		if os.IsNotExist(err) {
@@ -118,3 +209,12 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// ValidateGitHub checks that GitHub credentials are set. They're only
+// required once modals that file issues on GitHub are loaded.
+func (c *Config) ValidateGitHub() error {
+	if !c.UsesGithubApp() && c.GithubToken == "" {
+		return fmt.Errorf("%s is required unless %s is set", EnvGitHubToken, EnvGitHubAppID)
+	}
+	return nil
+}
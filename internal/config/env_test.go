@@ -53,6 +53,7 @@ func TestConfig_Validate(t *testing.T) {
 			errMsg:  "DISCORD_SERVER_ID is required",
 		},
 		{
+			// Checked by ValidateGitHub once the modals are loaded
 			name: "missing github token",
 			config: &Config{
 				DiscordToken: "test-token",
@@ -60,8 +61,52 @@ func TestConfig_Validate(t *testing.T) {
 				GithubToken:  "",
 				ConfigPath:   validConfigFile,
 			},
+			wantErr: false,
+		},
+		{
+			name: "GitHub App instead of a token",
+			config: &Config{
+				DiscordToken:            "test-token",
+				ServerID:                "123456",
+				GithubAppID:             "1234",
+				GithubAppPrivateKeyPath: "/secrets/app.pem",
+				ConfigPath:              validConfigFile,
+			},
+			wantErr: false,
+		},
+		{
+			name: "GitHub App without a private key",
+			config: &Config{
+				DiscordToken: "test-token",
+				ServerID:     "123456",
+				GithubAppID:  "1234",
+				ConfigPath:   validConfigFile,
+			},
 			wantErr: true,
-			errMsg:  "GITHUB_TOKEN is required",
+			errMsg:  "GITHUB_APP_PRIVATE_KEY or GITHUB_APP_PRIVATE_KEY_PATH is required",
+		},
+		{
+			name: "GitHub App ID is not a number",
+			config: &Config{
+				DiscordToken:        "test-token",
+				ServerID:            "123456",
+				GithubAppID:         "meshtastic-bot",
+				GithubAppPrivateKey: "key",
+				ConfigPath:          validConfigFile,
+			},
+			wantErr: true,
+			errMsg:  "GITHUB_APP_ID must be a number",
+		},
+		{
+			name: "attachments repository without a name",
+			config: &Config{
+				DiscordToken:          "test-token",
+				ServerID:              "123456",
+				ConfigPath:            validConfigFile,
+				AttachmentsRepository: "meshtastic",
+			},
+			wantErr: true,
+			errMsg:  "ATTACHMENTS_REPOSITORY: repository must be owner/repo",
 		},
 		{
 			name: "missing config path",
@@ -119,6 +164,26 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestConfig_ValidateGitHub(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{name: "token", config: &Config{GithubToken: "gh-token"}},
+		{name: "GitHub App", config: &Config{GithubAppID: "1234", GithubAppPrivateKey: "key"}},
+		{name: "neither", config: &Config{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.ValidateGitHub(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGitHub() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestSetDefaults(t *testing.T) {
 	cfg := &Config{}
 	setDefaults(cfg)
@@ -151,6 +216,18 @@ func TestSetDefaults(t *testing.T) {
 	if cfg.RemoveCommands != false {
 		t.Errorf("setDefaults() RemoveCommands = %v, want false", cfg.RemoveCommands)
 	}
+
+	if cfg.SessionTTL != DefaultSessionTTL {
+		t.Errorf("setDefaults() SessionTTL = %v, want %v", cfg.SessionTTL, DefaultSessionTTL)
+	}
+
+	if cfg.DraftRetention != DefaultDraftRetention {
+		t.Errorf("setDefaults() DraftRetention = %v, want %v", cfg.DraftRetention, DefaultDraftRetention)
+	}
+
+	if cfg.TemplateRefreshInterval != DefaultTemplateRefreshInterval {
+		t.Errorf("setDefaults() TemplateRefreshInterval = %v, want %v", cfg.TemplateRefreshInterval, DefaultTemplateRefreshInterval)
+	}
 }
 
 func TestLoadEnv(t *testing.T) {
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Base URLs of github.com. GitHub Enterprise Server, or a local stand-in,
+// is used by overriding them.
+const (
+	DefaultGitHubWebURL = "https://github.com"
+	DefaultGitHubAPIURL = "https://api.github.com"
+	DefaultGitHubRawURL = "https://raw.githubusercontent.com"
+)
+
+// GitHubURLs are the base URLs of the GitHub instance issues are filed on
+type GitHubURLs struct {
+	// Web is where repositories and issues are browsed, e.g. https://github.com
+	Web string
+	// API is the REST API, e.g. https://ghe.example.com/api/v3 on GitHub Enterprise Server
+	API string
+	// Raw serves file contents, e.g. https://ghe.example.com/raw on GitHub Enterprise Server
+	Raw string
+}
+
+// githubURLs are the base URLs template and issue URLs are built from
+var githubURLs = GitHubURLs{
+	Web: DefaultGitHubWebURL,
+	API: DefaultGitHubAPIURL,
+	Raw: DefaultGitHubRawURL,
+}
+
+// SetGitHubURLs replaces the github.com base URLs. It must be called before
+// modals are loaded.
+func SetGitHubURLs(urls GitHubURLs) {
+	urls = urls.withDefaults()
+	githubURLs = GitHubURLs{
+		Web: strings.TrimRight(urls.Web, "/"),
+		API: strings.TrimRight(urls.API, "/"),
+		Raw: strings.TrimRight(urls.Raw, "/"),
+	}
+}
+
+// GetGitHubURLs returns the base URLs of the GitHub instance
+func GetGitHubURLs() GitHubURLs {
+	return githubURLs
+}
+
+// GitHubWebHost returns the host of issue URLs, e.g. "github.com"
+func GitHubWebHost() string {
+	parsed, err := url.Parse(githubURLs.Web)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
+
+// withDefaults fills in github.com for any base URL that isn't set
+func (u GitHubURLs) withDefaults() GitHubURLs {
+	if u.Web == "" {
+		u.Web = DefaultGitHubWebURL
+	}
+	if u.API == "" {
+		u.API = DefaultGitHubAPIURL
+	}
+	if u.Raw == "" {
+		u.Raw = DefaultGitHubRawURL
+	}
+	return u
+}
+
+// validate checks that every base URL is an absolute http(s) URL
+func (u GitHubURLs) validate() error {
+	for envVar, value := range map[string]string{
+		EnvGitHubWebURL: u.Web,
+		EnvGitHubAPIURL: u.API,
+		EnvGitHubRawURL: u.Raw,
+	} {
+		if err := validateHTTPURL(envVar, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHTTPURL checks that a setting is an absolute http(s) URL
+func validateHTTPURL(name, value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL: %q", name, value)
+	}
+	return nil
+}
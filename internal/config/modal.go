@@ -2,12 +2,10 @@ package config
 
 import (
 	"fmt"
-	"io"
-	"net/http"
+	"log"
 	"os"
 	"strings"
 
-	"github.com/bwmarrin/discordgo"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,6 +23,10 @@ type FieldAttributes struct {
 	Value       string   `yaml:"value,omitempty"`
 	Options     []Option `yaml:"options,omitempty"`
 	Multiple    bool     `yaml:"multiple,omitempty"`
+	// Default is the index of the preselected dropdown option
+	Default *int `yaml:"default,omitempty"`
+	// Render is the language a textarea is rendered as a code block in
+	Render string `yaml:"render,omitempty"`
 }
 
 type FieldValidations struct {
@@ -42,19 +44,141 @@ type GitHubIssueTemplate struct {
 	Name        string                `yaml:"name"`
 	Description string                `yaml:"description"`
 	Title       string                `yaml:"title,omitempty"`
-	Labels      interface{}           `yaml:"labels,omitempty"`
+	Labels      StringList            `yaml:"labels,omitempty"`
+	Assignees   StringList            `yaml:"assignees,omitempty"`
 	Body        []GitHubTemplateField `yaml:"body"`
 }
 
+// StringList is a list of strings that issue forms may also write as a
+// single comma-separated string, e.g. `labels: bug, triage`
+type StringList []string
+
+// UnmarshalYAML accepts either a sequence or a comma-separated string
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	var list []string
+	if err := value.Decode(&list); err == nil {
+		*l = list
+		return nil
+	}
+
+	var str string
+	if err := value.Decode(&str); err != nil {
+		return err
+	}
+	*l = nil
+	for _, item := range strings.Split(str, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
 // Legacy FieldConfig for backwards compatibility
 type FieldConfig struct {
 	CustomID    string `yaml:"custom_id"`
+	Type        string `yaml:"type,omitempty"`
 	Label       string `yaml:"label"`
 	Style       string `yaml:"style"`
 	Placeholder string `yaml:"placeholder"`
 	Required    bool   `yaml:"required"`
 	MinLength   int    `yaml:"min_length"`
 	MaxLength   int    `yaml:"max_length"`
+	// Value prefills the text input
+	Value string `yaml:"value,omitempty"`
+	// Render wraps the answer in a code block of this language in the issue body
+	Render string `yaml:"render,omitempty"`
+
+	// Dropdown and checkboxes fields only
+	Options  []string `yaml:"options,omitempty"`
+	Multiple bool     `yaml:"multiple,omitempty"`
+	Default  string   `yaml:"default,omitempty"`
+	// RequiredOptions lists the checkboxes that must be ticked
+	RequiredOptions []string `yaml:"required_options,omitempty"`
+}
+
+// MaxSelectOptions is the number of options Discord allows in a select menu.
+// Dropdowns with more only show the first ones.
+const MaxSelectOptions = 25
+
+// validate checks that a dropdown or checkboxes field can be answered in a
+// select menu: it has options and every required option is shown
+func (f FieldConfig) validate() error {
+	if !f.IsSelect() {
+		return nil
+	}
+	if len(f.Options) == 0 {
+		return fmt.Errorf("%s %q has no options", f.Type, f.Label)
+	}
+	for _, required := range f.RequiredOptions {
+		index := indexOf(f.Options, required)
+		if index == -1 {
+			return fmt.Errorf("required option %q of %q is not one of its options", required, f.Label)
+		}
+		if index >= MaxSelectOptions {
+			return fmt.Errorf("required option %q of %q is past the first %d options, the most a select menu shows",
+				required, f.Label, MaxSelectOptions)
+		}
+	}
+	return nil
+}
+
+// indexOf returns the index of value in values, or -1
+func indexOf(values []string, value string) int {
+	for index, candidate := range values {
+		if candidate == value {
+			return index
+		}
+	}
+	return -1
+}
+
+// IsSelect reports whether the field is answered with a select menu
+// rather than a modal text input
+func (f FieldConfig) IsSelect() bool {
+	return f.Type == "dropdown" || f.IsCheckboxes()
+}
+
+// IsCheckboxes reports whether the field is a group of checkboxes
+func (f FieldConfig) IsCheckboxes() bool {
+	return f.Type == "checkboxes"
+}
+
+// ValidateSelection checks that every selected value is one of the field's
+// options, that a required field has at least one selection and that every
+// required checkbox is ticked
+func (f FieldConfig) ValidateSelection(selected []string) error {
+	for _, option := range f.RequiredOptions {
+		found := false
+		for _, value := range selected {
+			if value == option {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("you must confirm %q", option)
+		}
+	}
+	if f.Required && len(selected) == 0 {
+		return fmt.Errorf("%s is required", f.Label)
+	}
+	if !f.Multiple && len(selected) > 1 {
+		return fmt.Errorf("%s only accepts a single option", f.Label)
+	}
+	for _, value := range selected {
+		found := false
+		for _, option := range f.Options {
+			if option == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not a valid option for %s", value, f.Label)
+		}
+	}
+	return nil
 }
 
 type ModalConfig struct {
@@ -64,23 +188,45 @@ type ModalConfig struct {
 	Title          string        `yaml:"title"`
 	Fields         []FieldConfig `yaml:"fields,omitempty"`
 	ExcludeFields  []string      `yaml:"exclude_fields,omitempty"`
+	// Attachments enables /attach for reports from this modal
+	Attachments *AttachmentConfig `yaml:"attachments,omitempty"`
+	// Thread opens a Discord thread for every issue created from this modal
+	Thread bool `yaml:"thread,omitempty"`
+	// Labels replace the command's default labels, see DefaultLabels. The
+	// template's own labels are always added.
+	Labels []string `yaml:"labels,omitempty"`
+	// Assignees are added to the template's own assignees
+	Assignees []string `yaml:"assignees,omitempty"`
+	// Milestone is the title or number of the milestone issues are added to
+	Milestone string `yaml:"milestone,omitempty"`
+	// Project is the Projects (v2) board issues are added to, if any
+	Project *ProjectConfig `yaml:"project,omitempty"`
+	// TrackerRaw is where issues are filed: github (the default), gitlab,
+	// or gitea for Gitea and Forgejo
+	TrackerRaw string `yaml:"tracker,omitempty"`
+	// TrackerURL is the web URL of the GitLab or Gitea instance
+	TrackerURL string `yaml:"tracker_url,omitempty"`
+	// RepositoryRaw is the owner/repo issues are filed in when there's no
+	// template URL to take it from
+	RepositoryRaw string `yaml:"repository,omitempty"`
+	// TemplatePath is an issue form read from disk instead of a template
+	// URL, for trackers without GitHub issue forms. Relative paths are
+	// resolved against the directory of the config file.
+	TemplatePath string `yaml:"template_path,omitempty"`
 
 	// Parsed template URL (populated after loading)
 	TemplateURL *TemplateURL `yaml:"-"`
-}
-
-// ModalState tracks the state of multi-part modals
-type ModalState struct {
-	Title           string
-	AllFields       []FieldConfig
-	SubmittedValues map[string]string
-	Labels          []string
-	Command         string
-	ChannelID       string
+	// Parsed tracker, repository and local template (populated after loading).
+	// Repository is nil for legacy configs, which file in the default repository.
+	Tracker    Tracker              `yaml:"-"`
+	Repository *Repository          `yaml:"-"`
+	Template   *GitHubIssueTemplate `yaml:"-"`
 }
 
 type ModalsConfig struct {
 	Modals []ModalConfig `yaml:"config"`
+	// Unfurl is nil when issue references in chat are not unfurled
+	Unfurl *UnfurlConfig `yaml:"unfurl,omitempty"`
 }
 
 // UnmarshalYAML custom unmarshals an Option from either a string or an object
@@ -109,17 +255,17 @@ func (o *Option) UnmarshalYAML(value *yaml.Node) error {
 
 var loadedModals *ModalsConfig
 
-// GetOwnerAndRepo returns the owner and repo from the first configured modal template URL
-// Returns empty strings if no template URL is configured
+// GetOwnerAndRepo returns the owner and repo of the first modal filing on
+// GitHub, from its template URL or repository
+// Returns empty strings if no GitHub repository is configured
 func GetOwnerAndRepo() (string, string) {
 	if loadedModals == nil {
 		return "", ""
 	}
 
-	// Find the first modal with a template URL
 	for _, modal := range loadedModals.Modals {
-		if modal.TemplateURL != nil {
-			return modal.TemplateURL.Owner(), modal.TemplateURL.Repo()
+		if modal.Repository != nil && modal.Tracker.IsGitHub() {
+			return modal.Repository.Owner, modal.Repository.Repo
 		}
 	}
 
@@ -148,36 +294,35 @@ func LoadModals(ConfigPath string) error {
 			}
 			config.Modals[i].TemplateURL = parsedURL
 		}
+		if project := config.Modals[i].Project; project != nil {
+			if err := project.validate(); err != nil {
+				return fmt.Errorf("invalid project for command %s: %w", config.Modals[i].Command, err)
+			}
+		}
+		if err := config.Modals[i].resolveTracker(ConfigPath); err != nil {
+			return fmt.Errorf("invalid tracker for command %s: %w", config.Modals[i].Command, err)
+		}
+		for _, field := range config.Modals[i].Fields {
+			if err := field.validate(); err != nil {
+				return fmt.Errorf("invalid field for command %s: %w", config.Modals[i].Command, err)
+			}
+		}
+	}
+
+	if config.Unfurl != nil {
+		if err := config.Unfurl.validate(); err != nil {
+			return err
+		}
 	}
 
 	loadedModals = &config
 	return nil
 }
 
-// FetchGitHubTemplate fetches and parses a GitHub issue template from a TemplateURL
+// FetchGitHubTemplate returns the GitHub issue template for a TemplateURL,
+// from the template cache when it has already been fetched
 func FetchGitHubTemplate(templateURL *TemplateURL) (*GitHubIssueTemplate, error) {
-	resp, err := http.Get(templateURL.RawURL())
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch template: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch template from %s: status code %d",
-			templateURL.RawURL(), resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read template: %w", err)
-	}
-
-	var template GitHubIssueTemplate
-	if err := yaml.Unmarshal(data, &template); err != nil {
-		return nil, fmt.Errorf("failed to parse template YAML: %w", err)
-	}
-
-	return &template, nil
+	return getCachedTemplate(templateURL.RawURL())
 }
 
 // isFieldExcluded checks if a field ID is in the exclusion list (case-insensitive)
@@ -195,8 +340,8 @@ func isFieldExcluded(fieldID string, excludeList []string) bool {
 func GetTemplateFields(template *GitHubIssueTemplate) []GitHubTemplateField {
 	fields := make([]GitHubTemplateField, 0)
 	for _, field := range template.Body {
-		// Skip markdown and checkboxes fields as they're informational only
-		if field.Type != "markdown" && field.Type != "checkboxes" {
+		// Skip markdown fields as they're informational only
+		if field.Type != "markdown" {
 			fields = append(fields, field)
 		}
 	}
@@ -206,7 +351,7 @@ func GetTemplateFields(template *GitHubIssueTemplate) []GitHubTemplateField {
 // ConvertGitHubFieldToFieldConfig converts a GitHub template field to a FieldConfig
 func ConvertGitHubFieldToFieldConfig(field GitHubTemplateField) *FieldConfig {
 	// Skip non-interactive fields
-	if field.Type == "markdown" || field.Type == "checkboxes" {
+	if field.Type == "markdown" {
 		return nil
 	}
 
@@ -217,10 +362,13 @@ func ConvertGitHubFieldToFieldConfig(field GitHubTemplateField) *FieldConfig {
 
 	config := &FieldConfig{
 		CustomID:    field.ID,
+		Type:        field.Type,
 		Label:       field.Attributes.Label,
 		Style:       style,
 		Placeholder: field.Attributes.Placeholder,
 		Required:    field.Validations.Required,
+		Value:       field.Attributes.Value,
+		Render:      field.Attributes.Render,
 	}
 
 	// Set reasonable defaults for min/max length
@@ -231,172 +379,304 @@ func ConvertGitHubFieldToFieldConfig(field GitHubTemplateField) *FieldConfig {
 	case "textarea":
 		config.MinLength = 1
 		config.MaxLength = 4000
+	case "dropdown":
+		config.Style = ""
+		config.Multiple = field.Attributes.Multiple
+		for _, option := range field.Attributes.Options {
+			config.Options = append(config.Options, option.Label)
+		}
+		if index := field.Attributes.Default; index != nil && *index >= 0 && *index < len(config.Options) {
+			config.Default = config.Options[*index]
+		}
+	case "checkboxes":
+		config.Style = ""
+		config.Multiple = true
+		for _, option := range field.Attributes.Options {
+			config.Options = append(config.Options, option.Label)
+			if option.Required {
+				config.RequiredOptions = append(config.RequiredOptions, option.Label)
+			}
+		}
+		config.Required = len(config.RequiredOptions) > 0
 	}
 
 	return config
 }
 
-// GetAllFieldsForModal returns all fields for a modal config (used for multi-part modals)
-// Returns: fields, title, owner, repo, error
-func GetAllFieldsForModal(command, channelID string) ([]FieldConfig, string, string, string, error) {
+// ModalDefinition is a modal config resolved against its GitHub issue template
+type ModalDefinition struct {
+	// Name is the template name, or the configured title for legacy configs
+	Name string
+	// TitlePrefix is the template's issue title prefix, e.g. "[Bug]: "
+	TitlePrefix string
+	Fields      []FieldConfig
+	Owner       string
+	Repo        string
+	// Attachments is nil when reports can't have attachments
+	Attachments *AttachmentConfig
+	// Thread opens a companion Discord thread for every issue
+	Thread bool
+	// Labels are the configured or default labels followed by the template's
+	Labels    []string
+	Assignees []string
+	Milestone string
+	// Project is nil when issues aren't added to a project board
+	Project *ProjectConfig
+	// Tracker is where issues are filed, in the Owner/Repo repository
+	Tracker Tracker
+}
+
+// findModalConfig returns the modal config for a command in the given channel
+func findModalConfig(command, channelID string) (*ModalConfig, error) {
 	if loadedModals == nil {
-		return nil, "", "", "", fmt.Errorf("modals not loaded")
+		return nil, fmt.Errorf("modals not loaded")
 	}
 
-	// Find the matching modal config
-	var modalConfig *ModalConfig
-	for _, modal := range loadedModals.Modals {
-		if modal.Command == command {
-			// Check if this modal applies to the given channel
-			for _, cid := range modal.ChannelIDs {
-				if cid == channelID {
-					modalConfig = &modal
-					break
-				}
-			}
-			if modalConfig != nil {
-				break
+	for i := range loadedModals.Modals {
+		modal := &loadedModals.Modals[i]
+		if modal.Command != command {
+			continue
+		}
+		for _, cid := range modal.ChannelIDs {
+			if cid == channelID {
+				return modal, nil
 			}
 		}
 	}
 
-	if modalConfig == nil {
-		return nil, "", "", "", fmt.Errorf("no modal configured for command '%s' in channel '%s'", command, channelID)
+	return nil, fmt.Errorf("no modal configured for command '%s' in channel '%s'", command, channelID)
+}
+
+// Repository is a repository issues are created in
+type Repository struct {
+	Owner string
+	Repo  string
+}
+
+func (r Repository) String() string {
+	return r.Owner + "/" + r.Repo
+}
+
+// GetRepositories returns every distinct GitHub repository modals file
+// issues in, in config order
+func GetRepositories() []Repository {
+	if loadedModals == nil {
+		return nil
+	}
+
+	repositories := make([]Repository, 0)
+	seen := make(map[string]bool)
+	for _, modal := range loadedModals.Modals {
+		if modal.Repository == nil || !modal.Tracker.IsGitHub() {
+			continue
+		}
+		repository := *modal.Repository
+		key := strings.ToLower(repository.String())
+		if !seen[key] {
+			seen[key] = true
+			repositories = append(repositories, repository)
+		}
 	}
+	return repositories
+}
 
-	var fields []FieldConfig
-	var title string
-	var owner string
-	var repo string
+// RepositoryLabels are the labels the bot applies to issues in a repository
+type RepositoryLabels struct {
+	Repository
+	Tracker Tracker
+	Labels  []string
+}
 
-	// If template URL is configured, fetch and convert fields
-	if modalConfig.TemplateURL != nil {
-		template, err := FetchGitHubTemplate(modalConfig.TemplateURL)
-		if err != nil {
-			return nil, "", "", "", fmt.Errorf("failed to fetch template: %w", err)
+// GetRepositoryLabels returns every repository issues are created in, on
+// every tracker, with the labels of the modals that file there. Legacy modals
+// file in the first template's repository. A template's own labels are
+// included when it can be fetched.
+func GetRepositoryLabels() []RepositoryLabels {
+	if loadedModals == nil {
+		return nil
+	}
+
+	owner, repo := GetOwnerAndRepo()
+	repositories := make([]RepositoryLabels, 0)
+	indexes := make(map[string]int)
+	for index := range loadedModals.Modals {
+		modal := &loadedModals.Modals[index]
+		repository := Repository{Owner: owner, Repo: repo}
+		if modal.Repository != nil {
+			repository = *modal.Repository
+		}
+		labels := modal.labels()
+		if template, err := modal.template(); err != nil {
+			log.Printf("Not checking template labels of %s: %v", modal.TemplateURL, err)
+		} else if template != nil {
+			labels = mergeNames(labels, template.Labels)
+		}
+		if repository.Owner == "" {
+			continue
 		}
 
-		// Use template name as title
-		title = template.Name
-		// Extract owner and repo from template URL
-		owner = modalConfig.TemplateURL.Owner()
-		repo = modalConfig.TemplateURL.Repo()
+		key := modal.Tracker.String() + " " + strings.ToLower(repository.String())
+		if existing, ok := indexes[key]; ok {
+			repositories[existing].Labels = mergeNames(repositories[existing].Labels, labels)
+			continue
+		}
+		indexes[key] = len(repositories)
+		repositories = append(repositories, RepositoryLabels{Repository: repository, Tracker: modal.Tracker, Labels: mergeNames(labels)})
+	}
+	return repositories
+}
 
-		templateFields := GetTemplateFields(template)
-		for _, field := range templateFields {
-			// Skip excluded fields
-			if isFieldExcluded(field.ID, modalConfig.ExcludeFields) {
-				continue
-			}
-			if converted := ConvertGitHubFieldToFieldConfig(field); converted != nil {
-				fields = append(fields, *converted)
+// GetChannelRepository returns the first GitHub repository a modal in the
+// channel files issues in
+func GetChannelRepository(channelID string) (Repository, bool) {
+	if loadedModals == nil {
+		return Repository{}, false
+	}
+
+	for _, modal := range loadedModals.Modals {
+		if modal.Repository == nil || !modal.Tracker.IsGitHub() {
+			continue
+		}
+		for _, cid := range modal.ChannelIDs {
+			if cid == channelID {
+				return *modal.Repository, true
 			}
 		}
-	} else {
-		// Use configured fields
-		fields = modalConfig.Fields
-		title = modalConfig.Title
-		// For legacy configs without template URL, return empty owner/repo
-		owner = ""
-		repo = ""
 	}
-
-	return fields, title, owner, repo, nil
+	return Repository{}, false
 }
 
-// GetModel returns the modal data for a specific command and channel
-func GetModel(command, channelID string) (*discordgo.InteractionResponseData, error) {
+// GetChannelCommands returns the commands configured for a channel
+func GetChannelCommands(channelID string) []string {
 	if loadedModals == nil {
-		return nil, fmt.Errorf("modals not loaded")
+		return nil
 	}
 
-	// Find the matching modal config
-	var modalConfig *ModalConfig
+	commands := make([]string, 0)
 	for _, modal := range loadedModals.Modals {
-		if modal.Command == command {
-			for _, cid := range modal.ChannelIDs {
-				if cid == channelID {
-					modalConfig = &modal
-					break
-				}
-			}
-			if modalConfig != nil {
+		for _, cid := range modal.ChannelIDs {
+			if cid == channelID {
+				commands = append(commands, modal.Command)
 				break
 			}
 		}
 	}
+	return commands
+}
 
-	if modalConfig == nil {
-		return nil, fmt.Errorf("no modal configured for command '%s' in channel '%s'", command, channelID)
+// GetModalDefinition resolves the modal for a command and channel, reading
+// its issue template when one is configured
+func GetModalDefinition(command, channelID string) (*ModalDefinition, error) {
+	modalConfig, err := findModalConfig(command, channelID)
+	if err != nil {
+		return nil, err
 	}
 
-	var fields []FieldConfig
-	var title string
+	template, err := modalConfig.template()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch template: %w", err)
+	}
 
-	// If template URL is configured, fetch and convert fields
-	if modalConfig.TemplateURL != nil {
-		template, err := FetchGitHubTemplate(modalConfig.TemplateURL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch template: %w", err)
+	// Configs without a template use their configured fields
+	if template == nil {
+		definition := &ModalDefinition{
+			Name:        modalConfig.Title,
+			Fields:      modalConfig.Fields,
+			Attachments: modalConfig.Attachments,
+			Thread:      modalConfig.Thread,
+			Labels:      mergeNames(modalConfig.labels()),
+			Assignees:   mergeNames(modalConfig.Assignees),
+			Milestone:   modalConfig.Milestone,
+			Project:     modalConfig.Project,
+			Tracker:     modalConfig.Tracker,
+		}
+		if modalConfig.Repository != nil {
+			definition.Owner, definition.Repo = modalConfig.Repository.Owner, modalConfig.Repository.Repo
 		}
+		return definition, nil
+	}
 
-		// Use template name as title
-		title = template.Name
+	definition := &ModalDefinition{
+		Name:        template.Name,
+		TitlePrefix: template.Title,
+		Owner:       modalConfig.Repository.Owner,
+		Repo:        modalConfig.Repository.Repo,
+		Attachments: modalConfig.Attachments,
+		Thread:      modalConfig.Thread,
+		Labels:      mergeNames(modalConfig.labels(), template.Labels),
+		Assignees:   mergeNames(modalConfig.Assignees, template.Assignees),
+		Milestone:   modalConfig.Milestone,
+		Project:     modalConfig.Project,
+		Tracker:     modalConfig.Tracker,
+	}
 
-		templateFields := GetTemplateFields(template)
-		for _, field := range templateFields {
-			// Skip excluded fields
-			if isFieldExcluded(field.ID, modalConfig.ExcludeFields) {
-				continue
+	for index, field := range GetTemplateFields(template) {
+		// Skip excluded fields
+		if isFieldExcluded(field.ID, modalConfig.ExcludeFields) {
+			continue
+		}
+		if converted := ConvertGitHubFieldToFieldConfig(field); converted != nil {
+			// Template fields may omit their id, but answers are keyed by it
+			if converted.CustomID == "" {
+				converted.CustomID = fmt.Sprintf("field_%d", index)
 			}
-			if converted := ConvertGitHubFieldToFieldConfig(field); converted != nil {
-				fields = append(fields, *converted)
+			if err := converted.validate(); err != nil {
+				return nil, fmt.Errorf("invalid field in template %s: %w", modalConfig.TemplateURL, err)
 			}
+			definition.Fields = append(definition.Fields, *converted)
 		}
-	} else {
-		// Use configured fields
-		fields = modalConfig.Fields
-		title = modalConfig.Title
 	}
 
-	// Discord modals can only have 5 components max
-	// If there are more, we'll need multi-part modals (handled by the caller)
-	maxFields := 5
-	if len(fields) > maxFields {
-		fields = fields[:maxFields]
+	return definition, nil
+}
+
+// DefaultLabels returns the labels of issues from a command's modal when it
+// doesn't configure any
+func DefaultLabels(command string) []string {
+	switch command {
+	case "bug":
+		return []string{"from-discord", "bug"}
+	case "feature":
+		return []string{"from-discord", "enhancement"}
 	}
+	return []string{"from-discord"}
+}
 
-	// Build Discord modal components from the fields
-	components := make([]discordgo.MessageComponent, 0, len(fields))
-	for _, field := range fields {
-		style := discordgo.TextInputShort
-		if field.Style == "paragraph" {
-			style = discordgo.TextInputParagraph
-		}
+// template returns the modal's issue form, read from disk or fetched from its
+// template URL. It is nil for legacy configs.
+func (m *ModalConfig) template() (*GitHubIssueTemplate, error) {
+	if m.Template != nil {
+		return m.Template, nil
+	}
+	if m.TemplateURL == nil {
+		return nil, nil
+	}
+	return FetchGitHubTemplate(m.TemplateURL)
+}
 
-		textInput := discordgo.TextInput{
-			CustomID:    field.CustomID,
-			Label:       field.Label,
-			Style:       style,
-			Placeholder: field.Placeholder,
-			Required:    field.Required,
-		}
+// labels returns the configured labels, or the command's defaults
+func (m *ModalConfig) labels() []string {
+	if len(m.Labels) > 0 {
+		return m.Labels
+	}
+	return DefaultLabels(m.Command)
+}
 
-		if field.MinLength > 0 {
-			textInput.MinLength = field.MinLength
-		}
-		if field.MaxLength > 0 {
-			textInput.MaxLength = field.MaxLength
+// mergeNames joins lists of labels or usernames, dropping blanks and
+// case-insensitive duplicates
+func mergeNames(lists ...[]string) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, name := range list {
+			name = strings.TrimSpace(name)
+			key := strings.ToLower(name)
+			if name == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, name)
 		}
-
-		components = append(components, discordgo.ActionsRow{
-			Components: []discordgo.MessageComponent{textInput},
-		})
 	}
-
-	return &discordgo.InteractionResponseData{
-		CustomID:   fmt.Sprintf("modal_%s_%s", command, channelID),
-		Title:      title,
-		Components: components,
-	}, nil
+	return merged
 }
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestGetOwnerAndRepo(t *testing.T) {
@@ -113,3 +118,416 @@ func TestGetOwnerAndRepo_NoModalsLoaded(t *testing.T) {
 		t.Errorf("GetOwnerAndRepo() with no modals loaded, repo = %q, want empty string", repo)
 	}
 }
+
+func TestGetRepositories(t *testing.T) {
+	configYAML := `config:
+  - command: bug
+    template_url: https://github.com/meshtastic/web/blob/main/.github/ISSUE_TEMPLATE/bug.yml
+    channel_id:
+      - "111"
+  - command: feature
+    template_url: https://github.com/Meshtastic/Web/blob/main/.github/ISSUE_TEMPLATE/feature.yml
+    channel_id:
+      - "111"
+  - command: bug
+    template_url: https://github.com/meshtastic/Meshtastic-Android/blob/main/.github/ISSUE_TEMPLATE/bug_report.yml
+    channel_id:
+      - "222"
+      - "333"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	expected := []Repository{
+		{Owner: "meshtastic", Repo: "web"},
+		{Owner: "meshtastic", Repo: "Meshtastic-Android"},
+	}
+	if got := GetRepositories(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetRepositories() = %v, want %v", got, expected)
+	}
+
+	if got, ok := GetChannelRepository("333"); !ok || got != expected[1] {
+		t.Errorf("GetChannelRepository(333) = %v, %v, want %v", got, ok, expected[1])
+	}
+	if _, ok := GetChannelRepository("999"); ok {
+		t.Error("GetChannelRepository() found a repository for an unconfigured channel")
+	}
+}
+
+func TestGetModalDefinition_LegacyFields(t *testing.T) {
+	configYAML := `config:
+  - command: bug
+    channel_id:
+      - "123456789"
+    title: Bug Report
+    fields:
+      - custom_id: bug_title
+        label: Title
+        style: short
+        required: true
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	definition, err := GetModalDefinition("bug", "123456789")
+	if err != nil {
+		t.Fatalf("GetModalDefinition() unexpected error: %v", err)
+	}
+	if definition.Name != "Bug Report" {
+		t.Errorf("GetModalDefinition().Name = %q, want %q", definition.Name, "Bug Report")
+	}
+	if definition.TitlePrefix != "" {
+		t.Errorf("GetModalDefinition().TitlePrefix = %q, want empty string", definition.TitlePrefix)
+	}
+	if len(definition.Fields) != 1 || definition.Fields[0].CustomID != "bug_title" {
+		t.Errorf("GetModalDefinition().Fields = %+v, want the configured bug_title field", definition.Fields)
+	}
+
+	if _, err := GetModalDefinition("bug", "987654321"); err == nil {
+		t.Error("GetModalDefinition() for unconfigured channel expected error, got nil")
+	}
+}
+
+func TestConvertGitHubFieldToFieldConfig_Dropdown(t *testing.T) {
+	templateYAML := `name: Bug Report
+body:
+  - type: dropdown
+    id: platform
+    attributes:
+      label: Platform
+      multiple: true
+      options:
+        - Android
+        - iOS
+        - Web
+      default: 1
+    validations:
+      required: true
+`
+	var template GitHubIssueTemplate
+	if err := yaml.Unmarshal([]byte(templateYAML), &template); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	field := ConvertGitHubFieldToFieldConfig(template.Body[0])
+	if field == nil {
+		t.Fatal("ConvertGitHubFieldToFieldConfig() returned nil for dropdown")
+	}
+	if !field.IsSelect() {
+		t.Error("ConvertGitHubFieldToFieldConfig() dropdown IsSelect() = false, want true")
+	}
+	if !reflect.DeepEqual(field.Options, []string{"Android", "iOS", "Web"}) {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() Options = %v", field.Options)
+	}
+	if !field.Multiple {
+		t.Error("ConvertGitHubFieldToFieldConfig() Multiple = false, want true")
+	}
+	if field.Default != "iOS" {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() Default = %q, want %q", field.Default, "iOS")
+	}
+	if !field.Required {
+		t.Error("ConvertGitHubFieldToFieldConfig() Required = false, want true")
+	}
+}
+
+func TestFieldConfig_ValidateSelection(t *testing.T) {
+	single := FieldConfig{Label: "Channel", Type: "dropdown", Options: []string{"Stable", "Alpha"}, Required: true}
+	multiple := FieldConfig{Label: "Platforms", Type: "dropdown", Options: []string{"Android", "iOS"}, Multiple: true}
+
+	tests := []struct {
+		name     string
+		field    FieldConfig
+		selected []string
+		wantErr  bool
+	}{
+		{name: "valid single option", field: single, selected: []string{"Stable"}},
+		{name: "required without selection", field: single, selected: nil, wantErr: true},
+		{name: "several options on single select", field: single, selected: []string{"Stable", "Alpha"}, wantErr: true},
+		{name: "unknown option", field: single, selected: []string{"Beta"}, wantErr: true},
+		{name: "optional without selection", field: multiple, selected: nil},
+		{name: "several options on multiple select", field: multiple, selected: []string{"Android", "iOS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.field.ValidateSelection(tt.selected)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSelection(%v) error = %v, wantErr %v", tt.selected, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConvertGitHubFieldToFieldConfig_Checkboxes(t *testing.T) {
+	templateYAML := `name: Bug Report
+body:
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Acknowledgements
+      options:
+        - label: I have searched the existing issues
+          required: true
+        - label: I would like to work on this
+`
+	var template GitHubIssueTemplate
+	if err := yaml.Unmarshal([]byte(templateYAML), &template); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	if fields := GetTemplateFields(&template); len(fields) != 1 {
+		t.Fatalf("GetTemplateFields() returned %d fields, want checkboxes included", len(fields))
+	}
+
+	field := ConvertGitHubFieldToFieldConfig(template.Body[0])
+	if field == nil {
+		t.Fatal("ConvertGitHubFieldToFieldConfig() returned nil for checkboxes")
+	}
+	if !field.IsSelect() || !field.IsCheckboxes() {
+		t.Error("ConvertGitHubFieldToFieldConfig() checkboxes should be a select field")
+	}
+	if !reflect.DeepEqual(field.RequiredOptions, []string{"I have searched the existing issues"}) {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() RequiredOptions = %v", field.RequiredOptions)
+	}
+	if !field.Required || !field.Multiple {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() Required = %v, Multiple = %v, want both true", field.Required, field.Multiple)
+	}
+
+	if err := field.ValidateSelection([]string{"I would like to work on this"}); err == nil {
+		t.Error("ValidateSelection() without the required checkbox expected error, got nil")
+	}
+	if err := field.ValidateSelection([]string{"I have searched the existing issues"}); err != nil {
+		t.Errorf("ValidateSelection() with the required checkbox unexpected error: %v", err)
+	}
+}
+
+func TestConvertGitHubFieldToFieldConfig_ValueAndRender(t *testing.T) {
+	field := ConvertGitHubFieldToFieldConfig(GitHubTemplateField{
+		Type: "textarea",
+		ID:   "logs",
+		Attributes: FieldAttributes{
+			Label:  "Relevant log output",
+			Value:  "Paste logs here",
+			Render: "shell",
+		},
+	})
+
+	if field.Value != "Paste logs here" {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() Value = %q, want %q", field.Value, "Paste logs here")
+	}
+	if field.Render != "shell" {
+		t.Errorf("ConvertGitHubFieldToFieldConfig() Render = %q, want %q", field.Render, "shell")
+	}
+}
+
+func TestStringList_UnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected StringList
+	}{
+		{name: "sequence", yaml: "labels: [bug, triage]", expected: StringList{"bug", "triage"}},
+		{name: "comma-separated string", yaml: "labels: 'bug, triage,'", expected: StringList{"bug", "triage"}},
+		{name: "single string", yaml: "labels: bug", expected: StringList{"bug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var template GitHubIssueTemplate
+			if err := yaml.Unmarshal([]byte(tt.yaml), &template); err != nil {
+				t.Fatalf("yaml.Unmarshal() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(template.Labels, tt.expected) {
+				t.Errorf("Labels = %q, want %q", template.Labels, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetModalDefinition_LabelsAssigneesMilestone(t *testing.T) {
+	configYAML := `config:
+  - command: bug
+    template_url: https://github.com/meshtastic/Meshtastic-Android/blob/main/.github/ISSUE_TEMPLATE/bug_report.yml
+    channel_id:
+      - "111"
+    labels: [from-discord, Bug]
+    assignees: [maintainer]
+    milestone: "2.6"
+  - command: feature
+    channel_id:
+      - "111"
+    title: Feature Request
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	templateYAML := "name: Bug Report\nlabels: bug, triage\nassignees: [triager, Maintainer]\nbody: []\n"
+	var template GitHubIssueTemplate
+	if err := yaml.Unmarshal([]byte(templateYAML), &template); err != nil {
+		t.Fatalf("yaml.Unmarshal() unexpected error: %v", err)
+	}
+	rawURL := loadedModals.Modals[0].TemplateURL.RawURL()
+	templateCache[rawURL] = &templateCacheEntry{template: &template}
+	defer func() { templateCache = make(map[string]*templateCacheEntry) }()
+
+	definition, err := GetModalDefinition("bug", "111")
+	if err != nil {
+		t.Fatalf("GetModalDefinition() unexpected error: %v", err)
+	}
+	if expected := []string{"from-discord", "Bug", "triage"}; !reflect.DeepEqual(definition.Labels, expected) {
+		t.Errorf("Labels = %q, want %q", definition.Labels, expected)
+	}
+	if expected := []string{"maintainer", "triager"}; !reflect.DeepEqual(definition.Assignees, expected) {
+		t.Errorf("Assignees = %q, want %q", definition.Assignees, expected)
+	}
+	if definition.Milestone != "2.6" {
+		t.Errorf("Milestone = %q, want %q", definition.Milestone, "2.6")
+	}
+
+	// Modals without labels get the command's defaults
+	definition, err = GetModalDefinition("feature", "111")
+	if err != nil {
+		t.Fatalf("GetModalDefinition() unexpected error: %v", err)
+	}
+	if expected := DefaultLabels("feature"); !reflect.DeepEqual(definition.Labels, expected) {
+		t.Errorf("Labels = %q, want %q", definition.Labels, expected)
+	}
+}
+
+func TestLoadModals_Project(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		wantErr bool
+	}{
+		{name: "valid", project: "{owner: meshtastic, number: 5, fields: {Status: Triage}}"},
+		{name: "missing number", project: "{owner: meshtastic}", wantErr: true},
+		{name: "missing owner", project: "{number: 5}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configYAML := "config:\n  - command: bug\n    channel_id: [\"111\"]\n    project: " + tt.project + "\n"
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+				t.Fatalf("Failed to write temp config file: %v", err)
+			}
+			defer func() { loadedModals = nil }()
+
+			err := LoadModals(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadModals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			definition, err := GetModalDefinition("bug", "111")
+			if err != nil {
+				t.Fatalf("GetModalDefinition() unexpected error: %v", err)
+			}
+			if definition.Project == nil || definition.Project.Fields["Status"] != "Triage" {
+				t.Errorf("GetModalDefinition().Project = %+v", definition.Project)
+			}
+		})
+	}
+}
+
+func TestLoadModals_SelectFields(t *testing.T) {
+	manyOptions := make([]string, 0, MaxSelectOptions+1)
+	for index := 0; index <= MaxSelectOptions; index++ {
+		manyOptions = append(manyOptions, fmt.Sprintf("Option %d", index))
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		wantErr bool
+	}{
+		{name: "dropdown", field: "{custom_id: platform, type: dropdown, label: Platform, options: [Android, iOS]}"},
+		{name: "text input", field: "{custom_id: summary, label: Summary}"},
+		{name: "empty dropdown", field: "{custom_id: platform, type: dropdown, label: Platform}", wantErr: true},
+		{name: "required option shown", field: "{custom_id: terms, type: checkboxes, label: Terms, options: [" +
+			strings.Join(manyOptions, ", ") + "], required_options: [Option 0]}"},
+		{name: "required option past the menu", field: "{custom_id: terms, type: checkboxes, label: Terms, options: [" +
+			strings.Join(manyOptions, ", ") + "], required_options: [Option 25]}", wantErr: true},
+		{name: "required option missing", field: "{custom_id: terms, type: checkboxes, label: Terms, options: [Yes], required_options: [No]}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configYAML := "config:\n  - command: bug\n    channel_id: [\"111\"]\n    fields:\n      - " + tt.field + "\n"
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+				t.Fatalf("Failed to write temp config file: %v", err)
+			}
+			defer func() { loadedModals = nil }()
+
+			if err := LoadModals(configPath); (err != nil) != tt.wantErr {
+				t.Errorf("LoadModals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryLabels(t *testing.T) {
+	configYAML := `config:
+  - command: bug
+    template_url: https://github.com/meshtastic/firmware/blob/master/.github/ISSUE_TEMPLATE/bug.yml
+    channel_id: ["111"]
+  - command: feature
+    channel_id: ["111"]
+    labels: [from-discord, idea]
+  - command: bug
+    template_url: https://github.com/meshtastic/web/blob/main/.github/ISSUE_TEMPLATE/bug.yml
+    channel_id: ["222"]
+    labels: [web]
+  - command: gitlab
+    channel_id: ["333"]
+    tracker: gitlab
+    repository: meshtastic/firmware
+    labels: [Bug]
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	for _, modal := range []int{0, 2} {
+		templateCache[loadedModals.Modals[modal].TemplateURL.RawURL()] = &templateCacheEntry{
+			template: &GitHubIssueTemplate{Labels: StringList{"Bug", "triage"}},
+		}
+	}
+	defer func() { templateCache = make(map[string]*templateCacheEntry) }()
+
+	expected := []RepositoryLabels{
+		{Repository: Repository{Owner: "meshtastic", Repo: "firmware"}, Tracker: Tracker{Kind: TrackerGitHub}, Labels: []string{"from-discord", "bug", "triage", "idea"}},
+		{Repository: Repository{Owner: "meshtastic", Repo: "web"}, Tracker: Tracker{Kind: TrackerGitHub}, Labels: []string{"web", "Bug", "triage"}},
+		{Repository: Repository{Owner: "meshtastic", Repo: "firmware"}, Tracker: Tracker{Kind: TrackerGitLab, URL: DefaultGitLabURL}, Labels: []string{"Bug"}},
+	}
+	if got := GetRepositoryLabels(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("GetRepositoryLabels() = %+v, want %+v", got, expected)
+	}
+}
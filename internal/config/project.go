@@ -0,0 +1,22 @@
+package config
+
+import "fmt"
+
+// ProjectConfig adds issues created from a modal to a GitHub Projects (v2)
+// board, https://github.com/orgs/<owner>/projects/<number>
+type ProjectConfig struct {
+	// Owner is the login of the organization or user owning the project
+	Owner  string `yaml:"owner"`
+	Number int    `yaml:"number"`
+	// Fields are set on the new project item by field name, e.g.
+	// Status: Triage. Single select fields take the option's name.
+	Fields map[string]string `yaml:"fields,omitempty"`
+}
+
+// validate checks that the project is identified
+func (p *ProjectConfig) validate() error {
+	if p.Owner == "" || p.Number <= 0 {
+		return fmt.Errorf("project needs an owner and a number, got %q and %d", p.Owner, p.Number)
+	}
+	return nil
+}
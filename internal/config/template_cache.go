@@ -0,0 +1,189 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateCacheEntry is the last good copy of an issue template
+type templateCacheEntry struct {
+	template  *GitHubIssueTemplate
+	etag      string
+	fetchedAt time.Time
+}
+
+var (
+	// templateCache holds fetched issue templates keyed by raw template URL
+	templateCache   = make(map[string]*templateCacheEntry)
+	templateCacheMu sync.RWMutex
+	// templateFetches are the fetches in progress by raw template URL, so
+	// concurrent requests for a template wait for one fetch
+	templateFetches = make(map[string]*templateFetch)
+
+	templateHTTPClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// templateFetch is a fetch of a template, done is closed once template or
+// err is set
+type templateFetch struct {
+	done     chan struct{}
+	template *GitHubIssueTemplate
+	err      error
+}
+
+// getCachedTemplate returns a cached template, fetching it on a cache miss.
+// Configured templates are cached by WarmTemplateCache before the bot starts
+// and kept up to date by StartTemplateRefresher, so a miss doesn't keep an
+// interaction waiting.
+func getCachedTemplate(rawURL string) (*GitHubIssueTemplate, error) {
+	templateCacheMu.RLock()
+	entry, ok := templateCache[rawURL]
+	templateCacheMu.RUnlock()
+
+	if ok {
+		return entry.template, nil
+	}
+	return refreshTemplate(rawURL)
+}
+
+// refreshTemplate revalidates a template. Only the first request for a
+// template fetches it; concurrent ones wait for its result.
+func refreshTemplate(rawURL string) (*GitHubIssueTemplate, error) {
+	templateCacheMu.Lock()
+	if fetch, ok := templateFetches[rawURL]; ok {
+		templateCacheMu.Unlock()
+		<-fetch.done
+		return fetch.template, fetch.err
+	}
+	fetch := &templateFetch{done: make(chan struct{})}
+	templateFetches[rawURL] = fetch
+	templateCacheMu.Unlock()
+
+	fetch.template, fetch.err = fetchTemplate(rawURL)
+
+	templateCacheMu.Lock()
+	delete(templateFetches, rawURL)
+	templateCacheMu.Unlock()
+	close(fetch.done)
+	return fetch.template, fetch.err
+}
+
+// fetchTemplate revalidates a template with a conditional request. If the
+// request fails, the last good copy is served instead of an error.
+func fetchTemplate(rawURL string) (*GitHubIssueTemplate, error) {
+	templateCacheMu.RLock()
+	cached := templateCache[rawURL]
+	templateCacheMu.RUnlock()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template request: %w", err)
+	}
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := templateHTTPClient.Do(req)
+	if err != nil {
+		return staleOrError(rawURL, cached, fmt.Errorf("failed to fetch template: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		templateCacheMu.Lock()
+		templateCache[rawURL] = &templateCacheEntry{
+			template:  cached.template,
+			etag:      cached.etag,
+			fetchedAt: time.Now(),
+		}
+		templateCacheMu.Unlock()
+		return cached.template, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return staleOrError(rawURL, cached, fmt.Errorf("failed to fetch template from %s: status code %d",
+			rawURL, resp.StatusCode))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return staleOrError(rawURL, cached, fmt.Errorf("failed to read template: %w", err))
+	}
+
+	var template GitHubIssueTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return staleOrError(rawURL, cached, fmt.Errorf("failed to parse template YAML: %w", err))
+	}
+
+	templateCacheMu.Lock()
+	templateCache[rawURL] = &templateCacheEntry{
+		template:  &template,
+		etag:      resp.Header.Get("ETag"),
+		fetchedAt: time.Now(),
+	}
+	templateCacheMu.Unlock()
+
+	return &template, nil
+}
+
+// staleOrError returns the cached template when there is one, otherwise the error
+func staleOrError(rawURL string, cached *templateCacheEntry, err error) (*GitHubIssueTemplate, error) {
+	if cached == nil {
+		return nil, err
+	}
+	log.Printf("Serving cached template %s from %s: %v",
+		rawURL, cached.fetchedAt.Format(time.RFC3339), err)
+	return cached.template, nil
+}
+
+// WarmTemplateCache fetches or revalidates the template of every configured
+// modal. It returns an error for templates that have never been fetched;
+// ones with a cached copy keep serving it.
+func WarmTemplateCache() error {
+	if loadedModals == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, modal := range loadedModals.Modals {
+		if modal.TemplateURL == nil {
+			continue
+		}
+		if _, err := refreshTemplate(modal.TemplateURL.RawURL()); err != nil {
+			errs = append(errs, fmt.Errorf("command %s: %w", modal.Command, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartTemplateRefresher revalidates the cached templates every interval
+// until the context is cancelled
+func StartTemplateRefresher(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := WarmTemplateCache(); err != nil {
+					log.Printf("Failed to cache templates: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const testTemplateYAML = `name: Bug Report
+title: "[Bug]: "
+body:
+  - type: input
+    id: version
+    attributes:
+      label: Version
+`
+
+func TestRefreshTemplate(t *testing.T) {
+	var requests, notModified atomic.Int32
+	var failing atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(testTemplateYAML))
+	}))
+	defer server.Close()
+	defer func() { templateCache = make(map[string]*templateCacheEntry) }()
+
+	rawURL := server.URL + "/bug.yml"
+
+	// Cache miss fetches the template
+	template, err := getCachedTemplate(rawURL)
+	if err != nil {
+		t.Fatalf("getCachedTemplate() unexpected error: %v", err)
+	}
+	if template.Title != "[Bug]: " {
+		t.Errorf("getCachedTemplate().Title = %q, want %q", template.Title, "[Bug]: ")
+	}
+
+	// Cache hit doesn't make a request
+	if _, err := getCachedTemplate(rawURL); err != nil {
+		t.Fatalf("getCachedTemplate() unexpected error: %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("getCachedTemplate() made %d requests, want 1", got)
+	}
+
+	// Revalidation sends the ETag and keeps the cached copy
+	if _, err := refreshTemplate(rawURL); err != nil {
+		t.Fatalf("refreshTemplate() unexpected error: %v", err)
+	}
+	if got := notModified.Load(); got != 1 {
+		t.Errorf("refreshTemplate() got %d not modified responses, want 1", got)
+	}
+
+	// Failures serve the last good copy
+	failing.Store(true)
+	template, err = refreshTemplate(rawURL)
+	if err != nil {
+		t.Fatalf("refreshTemplate() with cached copy unexpected error: %v", err)
+	}
+	if template == nil || template.Name != "Bug Report" {
+		t.Errorf("refreshTemplate() = %+v, want the cached template", template)
+	}
+
+	// Failures without a cached copy return an error
+	if _, err := refreshTemplate(server.URL + "/missing.yml"); err == nil {
+		t.Error("refreshTemplate() without cached copy expected error, got nil")
+	}
+}
+
+func TestRefreshTemplate_ConcurrentMisses(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Write([]byte(testTemplateYAML))
+	}))
+	defer server.Close()
+	defer func() { templateCache = make(map[string]*templateCacheEntry) }()
+
+	rawURL := server.URL + "/bug.yml"
+	results := make(chan error, 5)
+	fetch := func() {
+		_, err := getCachedTemplate(rawURL)
+		results <- err
+	}
+
+	go fetch()
+	<-started
+	for i := 0; i < 4; i++ {
+		go fetch()
+	}
+	close(release)
+
+	for i := 0; i < 5; i++ {
+		if err := <-results; err != nil {
+			t.Fatalf("getCachedTemplate() unexpected error: %v", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("concurrent misses made %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue trackers a modal can file issues in
+const (
+	TrackerGitHub = "github"
+	TrackerGitLab = "gitlab"
+	// TrackerGitea is Gitea or its fork Forgejo, which share an API
+	TrackerGitea = "gitea"
+)
+
+// DefaultGitLabURL is the GitLab instance used when a modal doesn't name one
+const DefaultGitLabURL = "https://gitlab.com"
+
+// Tracker identifies the issue tracker a modal files issues in
+type Tracker struct {
+	Kind string
+	// URL is the web URL of a GitLab or Gitea instance, empty for GitHub
+	URL string
+}
+
+// IsGitHub reports whether issues are filed on the configured GitHub
+func (t Tracker) IsGitHub() bool {
+	return t.Kind == "" || t.Kind == TrackerGitHub
+}
+
+// Name is the tracker's name as shown to users
+func (t Tracker) Name() string {
+	switch t.Kind {
+	case TrackerGitLab:
+		return "GitLab"
+	case TrackerGitea:
+		return "Gitea"
+	}
+	return "GitHub"
+}
+
+func (t Tracker) String() string {
+	if t.IsGitHub() {
+		return TrackerGitHub
+	}
+	return t.Kind + " at " + t.URL
+}
+
+// parseTracker reads a modal's tracker and tracker_url options
+func parseTracker(kind, trackerURL string) (Tracker, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	trackerURL = strings.TrimRight(strings.TrimSpace(trackerURL), "/")
+
+	switch kind {
+	case "", TrackerGitHub:
+		if trackerURL != "" {
+			return Tracker{}, fmt.Errorf("tracker_url is only used with gitlab and gitea, GitHub is set with GITHUB_WEB_URL")
+		}
+		return Tracker{Kind: TrackerGitHub}, nil
+	case TrackerGitLab:
+		if trackerURL == "" {
+			trackerURL = DefaultGitLabURL
+		}
+	case TrackerGitea, "forgejo":
+		kind = TrackerGitea
+		if trackerURL == "" {
+			return Tracker{}, fmt.Errorf("tracker_url is required for gitea")
+		}
+	default:
+		return Tracker{}, fmt.Errorf("unknown tracker %q, expected github, gitlab or gitea", kind)
+	}
+
+	if err := validateHTTPURL("tracker_url", trackerURL); err != nil {
+		return Tracker{}, err
+	}
+	return Tracker{Kind: kind, URL: trackerURL}, nil
+}
+
+// parseRepository reads an "owner/repo" repository. GitLab projects may be
+// in nested groups, so everything before the last slash is the owner.
+func parseRepository(text string) (*Repository, error) {
+	text = strings.Trim(strings.TrimSpace(text), "/")
+	index := strings.LastIndex(text, "/")
+	if index <= 0 || index == len(text)-1 {
+		return nil, fmt.Errorf("repository must be owner/repo, got %q", text)
+	}
+	return &Repository{Owner: text[:index], Repo: text[index+1:]}, nil
+}
+
+// loadLocalTemplate reads an issue form from disk, resolving a relative path
+// against the directory of the modal config
+func loadLocalTemplate(path, configPath string) (*GitHubIssueTemplate, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(configPath), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var template GitHubIssueTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+	}
+	return &template, nil
+}
+
+// resolveTracker parses where a modal files issues and reads its local
+// template, once its template URL is parsed
+func (m *ModalConfig) resolveTracker(configPath string) error {
+	tracker, err := parseTracker(m.TrackerRaw, m.TrackerURL)
+	if err != nil {
+		return err
+	}
+	m.Tracker = tracker
+
+	if m.TemplateURL != nil {
+		switch {
+		case m.RepositoryRaw != "":
+			return fmt.Errorf("set either template_url or repository, not both")
+		case m.TemplatePath != "":
+			return fmt.Errorf("set either template_url or template_path, not both")
+		case !tracker.IsGitHub():
+			return fmt.Errorf("template_url only works with GitHub, use template_path and repository with %s", tracker.Kind)
+		}
+		m.Repository = &Repository{Owner: m.TemplateURL.Owner(), Repo: m.TemplateURL.Repo()}
+	}
+	if m.RepositoryRaw != "" {
+		if m.Repository, err = parseRepository(m.RepositoryRaw); err != nil {
+			return err
+		}
+	}
+
+	if m.TemplatePath != "" {
+		if m.Repository == nil {
+			return fmt.Errorf("template_path needs a repository to file issues in")
+		}
+		if m.Template, err = loadLocalTemplate(m.TemplatePath, configPath); err != nil {
+			return err
+		}
+	}
+
+	if !tracker.IsGitHub() {
+		if m.Repository == nil {
+			return fmt.Errorf("%s needs a repository to file issues in", tracker.Kind)
+		}
+		if m.Project != nil {
+			return fmt.Errorf("project boards are only supported on GitHub")
+		}
+	}
+	return nil
+}
+
+// UsesGitHub reports whether any modal files issues on GitHub. The bot only
+// needs GitHub credentials when one does.
+func UsesGitHub() bool {
+	if loadedModals == nil {
+		return false
+	}
+
+	for _, modal := range loadedModals.Modals {
+		if modal.Tracker.IsGitHub() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTrackers returns every GitLab and Gitea instance modals file issues in
+func GetTrackers() []Tracker {
+	if loadedModals == nil {
+		return nil
+	}
+
+	trackers := make([]Tracker, 0)
+	seen := make(map[Tracker]bool)
+	for _, modal := range loadedModals.Modals {
+		if modal.Tracker.IsGitHub() || seen[modal.Tracker] {
+			continue
+		}
+		seen[modal.Tracker] = true
+		trackers = append(trackers, modal.Tracker)
+	}
+	return trackers
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadModals_Tracker(t *testing.T) {
+	tests := []struct {
+		name        string
+		modal       string
+		wantErr     bool
+		wantTracker Tracker
+		wantRepo    *Repository
+	}{
+		{
+			name:        "github by default",
+			modal:       "template_url: https://github.com/meshtastic/firmware/blob/master/.github/ISSUE_TEMPLATE/bug.yml",
+			wantTracker: Tracker{Kind: TrackerGitHub},
+			wantRepo:    &Repository{Owner: "meshtastic", Repo: "firmware"},
+		},
+		{
+			name:        "gitlab.com by default",
+			modal:       "tracker: gitlab\n    repository: community/sub/firmware",
+			wantTracker: Tracker{Kind: TrackerGitLab, URL: DefaultGitLabURL},
+			wantRepo:    &Repository{Owner: "community/sub", Repo: "firmware"},
+		},
+		{
+			name:        "forgejo",
+			modal:       "tracker: forgejo\n    tracker_url: https://codeberg.org/\n    repository: community/firmware",
+			wantTracker: Tracker{Kind: TrackerGitea, URL: "https://codeberg.org"},
+			wantRepo:    &Repository{Owner: "community", Repo: "firmware"},
+		},
+		{name: "unknown tracker", modal: "tracker: jira\n    repository: community/firmware", wantErr: true},
+		{name: "gitea without url", modal: "tracker: gitea\n    repository: community/firmware", wantErr: true},
+		{name: "gitlab without repository", modal: "tracker: gitlab", wantErr: true},
+		{name: "invalid repository", modal: "tracker: gitlab\n    repository: firmware", wantErr: true},
+		{name: "tracker_url on github", modal: "tracker_url: https://gitlab.com\n    repository: meshtastic/firmware", wantErr: true},
+		{
+			name:    "template url on gitlab",
+			modal:   "tracker: gitlab\n    template_url: https://github.com/meshtastic/firmware/blob/master/.github/ISSUE_TEMPLATE/bug.yml",
+			wantErr: true,
+		},
+		{
+			name:    "project on gitlab",
+			modal:   "tracker: gitlab\n    repository: community/firmware\n    project: {owner: meshtastic, number: 5}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configYAML := "config:\n  - command: bug\n    channel_id: [\"111\"]\n    " + tt.modal + "\n"
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+				t.Fatalf("Failed to write temp config file: %v", err)
+			}
+			defer func() { loadedModals = nil }()
+
+			err := LoadModals(configPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadModals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			modal := loadedModals.Modals[0]
+			if modal.Tracker != tt.wantTracker {
+				t.Errorf("Tracker = %+v, want %+v", modal.Tracker, tt.wantTracker)
+			}
+			if !reflect.DeepEqual(modal.Repository, tt.wantRepo) {
+				t.Errorf("Repository = %+v, want %+v", modal.Repository, tt.wantRepo)
+			}
+		})
+	}
+}
+
+func TestGetModalDefinition_LocalTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templateYAML := `name: Bug Report
+title: "[Bug]: "
+labels: [bug]
+body:
+  - type: textarea
+    id: what-happened
+    attributes:
+      label: What happened?
+    validations:
+      required: true
+`
+	if err := os.MkdirAll(filepath.Join(dir, "templates"), 0755); err != nil {
+		t.Fatalf("Failed to create template dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "templates", "bug.yml"), []byte(templateYAML), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	configYAML := `config:
+  - command: bug
+    channel_id: ["111"]
+    tracker: gitea
+    tracker_url: https://gitea.example
+    repository: community/firmware
+    template_path: templates/bug.yml
+  - command: bug
+    channel_id: ["222"]
+    template_url: https://github.com/meshtastic/firmware/blob/master/.github/ISSUE_TEMPLATE/bug.yml
+`
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	definition, err := GetModalDefinition("bug", "111")
+	if err != nil {
+		t.Fatalf("GetModalDefinition() unexpected error: %v", err)
+	}
+	if definition.Name != "Bug Report" || definition.TitlePrefix != "[Bug]: " {
+		t.Errorf("GetModalDefinition() name %q and prefix %q", definition.Name, definition.TitlePrefix)
+	}
+	if definition.Owner != "community" || definition.Repo != "firmware" {
+		t.Errorf("GetModalDefinition() repository %s/%s, want community/firmware", definition.Owner, definition.Repo)
+	}
+	if definition.Tracker != (Tracker{Kind: TrackerGitea, URL: "https://gitea.example"}) {
+		t.Errorf("GetModalDefinition().Tracker = %+v", definition.Tracker)
+	}
+	if len(definition.Fields) != 1 || definition.Fields[0].CustomID != "what-happened" || !definition.Fields[0].Required {
+		t.Errorf("GetModalDefinition().Fields = %+v", definition.Fields)
+	}
+	if !reflect.DeepEqual(definition.Labels, []string{"from-discord", "bug"}) {
+		t.Errorf("GetModalDefinition().Labels = %v", definition.Labels)
+	}
+
+	// The Gitea repository isn't one of the GitHub repositories
+	if got := GetRepositories(); !reflect.DeepEqual(got, []Repository{{Owner: "meshtastic", Repo: "firmware"}}) {
+		t.Errorf("GetRepositories() = %+v", got)
+	}
+	if got := GetTrackers(); !reflect.DeepEqual(got, []Tracker{{Kind: TrackerGitea, URL: "https://gitea.example"}}) {
+		t.Errorf("GetTrackers() = %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxUnfurls is the number of issue references unfurled per message
+// when the unfurl block leaves it unset. Discord allows at most
+// MaxUnfurls embeds in a message.
+const (
+	DefaultMaxUnfurls = 3
+	MaxUnfurls        = 10
+)
+
+// UnfurlConfig enables replies with an embed for issue references such as
+// "#1234", "Meshtastic-Android#567" or issue URLs typed in configured channels
+type UnfurlConfig struct {
+	// MaxPerMessage limits the embeds in a reply, extra references are ignored
+	MaxPerMessage int `yaml:"max_per_message,omitempty"`
+	// Aliases map shorthand names to repositories, e.g. "android" to
+	// "meshtastic/Meshtastic-Android", for references like "android#567"
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// Limit returns the number of references unfurled per message
+func (u *UnfurlConfig) Limit() int {
+	if u.MaxPerMessage > MaxUnfurls {
+		return MaxUnfurls
+	}
+	if u.MaxPerMessage > 0 {
+		return u.MaxPerMessage
+	}
+	return DefaultMaxUnfurls
+}
+
+// validate checks that every alias names an owner/repo
+func (u *UnfurlConfig) validate() error {
+	for alias, target := range u.Aliases {
+		owner, repo, found := strings.Cut(target, "/")
+		if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return fmt.Errorf("unfurl alias %q must name an owner/repo, got %q", alias, target)
+		}
+	}
+	return nil
+}
+
+// GetUnfurlConfig returns the unfurl settings, or nil when unfurling is disabled
+func GetUnfurlConfig() *UnfurlConfig {
+	if loadedModals == nil {
+		return nil
+	}
+	return loadedModals.Unfurl
+}
+
+// ResolveRepoAlias returns the repository for a shorthand name: an unfurl
+// alias, or the name of a configured repository. Names are case-insensitive.
+func ResolveRepoAlias(name string) (Repository, bool) {
+	if loadedModals == nil {
+		return Repository{}, false
+	}
+
+	if loadedModals.Unfurl != nil {
+		for alias, target := range loadedModals.Unfurl.Aliases {
+			if strings.EqualFold(alias, name) {
+				owner, repo, _ := strings.Cut(target, "/")
+				return Repository{Owner: owner, Repo: repo}, true
+			}
+		}
+	}
+
+	for _, repository := range GetRepositories() {
+		if strings.EqualFold(repository.Repo, name) {
+			return repository, true
+		}
+	}
+	return Repository{}, false
+}
+
+// IsKnownRepository reports whether a GitHub repository is one modals file
+// issues in or an unfurl alias names. Only these repositories' issues are
+// shown in Discord, so private repositories the bot can read aren't leaked.
+func IsKnownRepository(owner, repo string) bool {
+	if loadedModals == nil {
+		return false
+	}
+
+	known := GetRepositories()
+	if loadedModals.Unfurl != nil {
+		for _, target := range loadedModals.Unfurl.Aliases {
+			aliasOwner, aliasRepo, _ := strings.Cut(target, "/")
+			known = append(known, Repository{Owner: aliasOwner, Repo: aliasRepo})
+		}
+	}
+	for _, repository := range known {
+		if strings.EqualFold(repository.Owner, owner) && strings.EqualFold(repository.Repo, repo) {
+			return true
+		}
+	}
+	return false
+}
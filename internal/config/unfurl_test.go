@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnfurlConfig_Limit(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		expected int
+	}{
+		{name: "unset", max: 0, expected: DefaultMaxUnfurls},
+		{name: "configured", max: 5, expected: 5},
+		{name: "above Discord's embed limit", max: 20, expected: MaxUnfurls},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unfurl := &UnfurlConfig{MaxPerMessage: tt.max}
+			if got := unfurl.Limit(); got != tt.expected {
+				t.Errorf("Limit() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveRepoAlias(t *testing.T) {
+	configYAML := `config:
+  - command: bug
+    template_url: https://github.com/meshtastic/Meshtastic-Android/blob/main/.github/ISSUE_TEMPLATE/bug_report.yml
+    channel_id:
+      - "111"
+unfurl:
+  aliases:
+    fw: meshtastic/firmware
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	if err := LoadModals(configPath); err != nil {
+		t.Fatalf("LoadModals() error = %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	tests := []struct {
+		name     string
+		expected Repository
+		found    bool
+	}{
+		{name: "FW", expected: Repository{Owner: "meshtastic", Repo: "firmware"}, found: true},
+		{name: "meshtastic-android", expected: Repository{Owner: "meshtastic", Repo: "Meshtastic-Android"}, found: true},
+		{name: "web", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveRepoAlias(tt.name)
+			if ok != tt.found || got != tt.expected {
+				t.Errorf("ResolveRepoAlias(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.expected, tt.found)
+			}
+		})
+	}
+
+	known := []struct {
+		owner, repo string
+		expected    bool
+	}{
+		{owner: "meshtastic", repo: "meshtastic-android", expected: true},
+		{owner: "Meshtastic", repo: "firmware", expected: true},
+		{owner: "meshtastic", repo: "private-infra", expected: false},
+		{owner: "someone", repo: "firmware", expected: false},
+	}
+	for _, tt := range known {
+		if got := IsKnownRepository(tt.owner, tt.repo); got != tt.expected {
+			t.Errorf("IsKnownRepository(%q, %q) = %v, want %v", tt.owner, tt.repo, got, tt.expected)
+		}
+	}
+}
+
+func TestLoadModals_InvalidUnfurlAlias(t *testing.T) {
+	configYAML := `config: []
+unfurl:
+  aliases:
+    fw: firmware
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configYAML), 0644); err != nil {
+		t.Fatalf("Failed to write temp config file: %v", err)
+	}
+	defer func() { loadedModals = nil }()
+
+	if err := LoadModals(configPath); err == nil {
+		t.Error("LoadModals() accepted an alias without an owner")
+	}
+}
@@ -4,9 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/meshtastic/meshtastic-bot/internal/config"
 	"github.com/meshtastic/meshtastic-bot/internal/discord/handlers"
+	"github.com/meshtastic/meshtastic-bot/internal/gitea"
+	"github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/gitlab"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -16,6 +25,15 @@ type DiscordBot struct {
 	config   *config.Config
 	logger   *log.Logger
 	commands []*discordgo.ApplicationCommand
+	issues   *store.IssueStore
+	// github is nil when no modal files issues on GitHub
+	github *github.Client
+	// trackers are the clients of the GitLab and Gitea instances modals file
+	// issues in
+	trackers map[config.Tracker]tracker.IssueTracker
+	// repositoriesReady is set once every configured repository passed its
+	// check, see checkRepositories
+	repositoriesReady atomic.Bool
 }
 
 func New(cfg *config.Config, logger *log.Logger) (*DiscordBot, error) {
@@ -23,20 +41,89 @@ func New(cfg *config.Config, logger *log.Logger) (*DiscordBot, error) {
 		logger = log.Default()
 	}
 
+	// Template and issue URLs are resolved against these while modals load
+	config.SetGitHubURLs(cfg.GitHubURLs())
 	if err := config.LoadModals(cfg.ConfigPath); err != nil {
 		return nil, fmt.Errorf("failed to load modals: %w", err)
 	}
+	// Modals open within Discord's interaction deadline only when their
+	// templates are cached, so the bot doesn't start without them
+	if err := config.WarmTemplateCache(); err != nil {
+		return nil, fmt.Errorf("failed to fetch issue templates: %w", err)
+	}
 
 	if _, err := config.LoadFAQ(cfg.FAQPath); err != nil {
 		return nil, fmt.Errorf("failed to load FAQ: %w", err)
 	}
 
-	owner, repo := config.GetOwnerAndRepo()
-	if owner == "" || repo == "" {
-		return nil, fmt.Errorf("failed to extract owner/repo from config template URLs")
+	var githubClient *github.Client
+	if config.UsesGitHub() {
+		owner, repo := config.GetOwnerAndRepo()
+		if owner == "" || repo == "" {
+			return nil, fmt.Errorf("failed to extract owner/repo from config template URLs")
+		}
+		if err := cfg.ValidateGitHub(); err != nil {
+			return nil, err
+		}
+		client, err := newGithubClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		githubClient = client
+		handlers.InitializeGithub(githubClient, owner, repo)
+		logger.Printf("Initialized GitHub client for %s/%s", owner, repo)
+	} else {
+		logger.Println("No modal files issues on GitHub, /issue and unfurling are disabled")
+	}
+
+	trackers, err := newTrackerClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+	handlers.InitializeTrackers(trackers)
+
+	attachmentRepo, err := cfg.AttachmentsRepo()
+	if err != nil {
+		return nil, err
+	}
+	if attachmentRepo != nil {
+		if githubClient == nil {
+			return nil, fmt.Errorf("%s needs a modal that files issues on GitHub", config.EnvAttachmentsRepository)
+		}
+		handlers.InitializeAttachments(attachmentRepo)
+		logger.Printf("Committing attached files to %s", attachmentRepo)
+	}
+
+	var issueStore *store.IssueStore
+	if cfg.DataDir != "" {
+		sessionStore, err := handlers.NewFileSessionStore(filepath.Join(cfg.DataDir, "sessions.json"), cfg.SessionTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sessions: %w", err)
+		}
+		handlers.InitializeSessions(sessionStore)
+
+		draftStore, err := handlers.NewFileDraftStore(filepath.Join(cfg.DataDir, "drafts.json"), cfg.DraftRetention)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load drafts: %w", err)
+		}
+		handlers.InitializeDrafts(draftStore)
+
+		outbox, err := handlers.NewFileOutbox(filepath.Join(cfg.DataDir, "outbox.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load outbox: %w", err)
+		}
+		handlers.InitializeOutbox(outbox)
+
+		issueStore, err = store.NewFileIssueStore(filepath.Join(cfg.DataDir, "issues.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load issue records: %w", err)
+		}
+	} else {
+		handlers.InitializeSessions(handlers.NewMemorySessionStore(cfg.SessionTTL))
+		handlers.InitializeDrafts(handlers.NewDraftStore(cfg.DraftRetention))
+		issueStore = store.NewIssueStore()
 	}
-	handlers.InitializeGithub(cfg.GithubToken, owner, repo)
-	logger.Printf("Initialized GitHub client for %s/%s", owner, repo)
+	handlers.InitializeIssues(issueStore)
 
 	session, err := discordgo.New("Bot " + cfg.DiscordToken)
 	if err != nil {
@@ -48,20 +135,96 @@ func New(cfg *config.Config, logger *log.Logger) (*DiscordBot, error) {
 		config:   cfg,
 		logger:   logger,
 		commands: getCommands(),
+		issues:   issueStore,
+		github:   githubClient,
+		trackers: trackers,
 	}
 
 	bot.session.AddHandler(handlers.HandleInteraction)
+	if config.GetUnfurlConfig() != nil {
+		// Reading issue references needs the privileged message content intent
+		bot.session.Identify.Intents |= discordgo.IntentGuildMessages | discordgo.IntentMessageContent
+		bot.session.AddHandler(handlers.HandleMessageCreate)
+	}
 	bot.session.AddHandler(bot.handleReady)
 
 	return bot, nil
 }
 
+// newGithubClient authenticates as the configured GitHub App, or with the
+// personal access token, against the configured API
+func newGithubClient(cfg *config.Config) (*github.Client, error) {
+	client, err := newGithubAuthClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetAPIURL(cfg.GitHubURLs().API); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// newTrackerClients creates a client for every GitLab and Gitea instance
+// modals file issues in
+func newTrackerClients(cfg *config.Config) (map[config.Tracker]tracker.IssueTracker, error) {
+	clients := make(map[config.Tracker]tracker.IssueTracker)
+	for _, t := range config.GetTrackers() {
+		token := cfg.TrackerToken(t)
+		if token == "" {
+			return nil, fmt.Errorf("modals file issues on %s, but no %s token is set", t, t.Name())
+		}
+
+		var client tracker.IssueTracker
+		var err error
+		switch t.Kind {
+		case config.TrackerGitLab:
+			client, err = gitlab.NewClient(t.URL, token)
+		case config.TrackerGitea:
+			client, err = gitea.NewClient(t.URL, token)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for %s: %w", t, err)
+		}
+		clients[t] = client
+	}
+	return clients, nil
+}
+
+// newGithubAuthClient creates the client for the configured authentication
+func newGithubAuthClient(cfg *config.Config) (*github.Client, error) {
+	if !cfg.UsesGithubApp() {
+		return github.NewClient(cfg.GithubToken), nil
+	}
+
+	appID, err := strconv.ParseInt(cfg.GithubAppID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub App ID: %w", err)
+	}
+	key, err := cfg.GithubAppKey()
+	if err != nil {
+		return nil, err
+	}
+	client, err := github.NewAppClient(appID, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up GitHub App authentication: %w", err)
+	}
+	return client, nil
+}
+
+// Start connects to Discord. GitHub calls are cancelled when ctx is.
 func (b *DiscordBot) Start(ctx context.Context) error {
+	handlers.InitializeContext(ctx)
+
 	b.logger.Println("Opening DiscordBot session...")
 	if err := b.session.Open(); err != nil {
 		return fmt.Errorf("failed to open session: %w", err)
 	}
 
+	config.StartTemplateRefresher(ctx, b.config.TemplateRefreshInterval)
+	handlers.StartSessionEviction(ctx, time.Minute)
+	handlers.StartOutbox(ctx, b.session, 15*time.Second)
+	go b.checkRepositories(ctx)
+
 	b.logger.Println("Registering slash commands...")
 	if err := b.registerCommands(); err != nil {
 		b.session.Close()
@@ -72,9 +235,15 @@ func (b *DiscordBot) Start(ctx context.Context) error {
 	return nil
 }
 
+// Stop waits until ctx is done for interactions in progress, like issues
+// being created, then disconnects from Discord
 func (b *DiscordBot) Stop(ctx context.Context) error {
 	b.logger.Println("Shutting down bot...")
 
+	if err := handlers.Drain(ctx); err != nil {
+		b.logger.Printf("Stopped waiting for in-flight interactions: %v", err)
+	}
+
 	if b.config.RemoveCommands {
 		b.logger.Println("Removing registered commands...")
 		if err := b.removeCommands(); err != nil {
@@ -136,3 +305,9 @@ func (b *DiscordBot) handleReady(s *discordgo.Session, r *discordgo.Ready) {
 func (b *DiscordBot) IsHealthy() bool {
 	return b.session != nil && b.session.DataReady
 }
+
+// IsReady returns true once the bot is healthy and can create issues in
+// every configured repository
+func (b *DiscordBot) IsReady() bool {
+	return b.IsHealthy() && b.repositoriesReady.Load()
+}
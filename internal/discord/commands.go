@@ -48,5 +48,52 @@ func getCommands() []*discordgo.ApplicationCommand {
 				},
 			},
 		},
+		{
+			Name:        "issue",
+			Description: "Show a GitHub issue or pull request",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "issue",
+					Description:  "An issue number, repo#number or issue URL",
+					Required:     true,
+					Autocomplete: true,
+				},
+			},
+		},
+		{
+			Name:        "myissues",
+			Description: "List the issues you filed through the bot and their current state",
+		},
+		{
+			Name: "Report as bug",
+			Type: discordgo.MessageApplicationCommand,
+		},
+		{
+			Name:        "attach",
+			Description: "Attach screenshots or logs to the report you're writing",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        "file",
+					Description: "A screenshot or log file",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        "file2",
+					Description: "Another file",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        "file3",
+					Description: "Another file",
+				},
+			},
+		},
+		{
+			Name:        "drafts",
+			Description: "Resume or discard your unfinished bug reports and feature requests",
+		},
 	}
 }
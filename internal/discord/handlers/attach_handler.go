@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxInlineText is how much text from attached logs is inlined in a comment,
+// keeping it under GitHub's 65536 character limit
+const maxInlineText = 50000
+
+// attachmentHTTPClient downloads attachments from Discord's CDN
+var attachmentHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// attachmentRepo is the GitHub repository attached files are committed to,
+// nil when they're linked from Discord
+var attachmentRepo *config.Repository
+
+// InitializeAttachments commits files from /attach to a GitHub repository
+func InitializeAttachments(repository *config.Repository) {
+	attachmentRepo = repository
+}
+
+// Attachment is a file added to a report with /attach. Discord's links to
+// files are signed and expire after about a day, so text files are
+// downloaded right away and inlined in the issue. Other files are committed
+// to the attachments repository when one is configured, otherwise they're
+// linked and only last as long as the link.
+type Attachment struct {
+	Filename    string `json:"filename"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	// Text is the content of a text file, empty when it couldn't be
+	// downloaded
+	Text string `json:"text,omitempty"`
+	// Hosted is set once the file is committed to the attachments
+	// repository and URL links there
+	Hosted bool `json:"hosted,omitempty"`
+}
+
+// IsText reports whether the attachment's content can be inlined
+func (a Attachment) IsText() bool {
+	return strings.HasPrefix(a.ContentType, "text/") || strings.HasPrefix(a.ContentType, "application/json")
+}
+
+// handleAttach adds the files from /attach to the user's report in progress
+// in this channel
+func handleAttach(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	stateKey, state := findAttachableReport(i.ChannelID, i.Member.User.ID)
+	if state == nil {
+		respondEphemeral(s, i, "❌ You don't have a report in progress in this channel that accepts attachments. "+
+			"Start one with /bug or /feature first.")
+		return
+	}
+
+	// Downloading and committing files can take longer than Discord waits for a response
+	if err := deferEphemeralResponse(s, i); err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+
+	// Files are checked against a copy of the report first, so only accepted
+	// files are downloaded, and again when they're added to the session
+	data := i.ApplicationCommandData()
+	var accepted []Attachment
+	var added, rejected []string
+	for _, option := range data.Options {
+		if option.Type != discordgo.ApplicationCommandOptionAttachment || data.Resolved == nil {
+			continue
+		}
+		id, _ := option.Value.(string)
+		file, ok := data.Resolved.Attachments[id]
+		if !ok {
+			continue
+		}
+
+		attachment := Attachment{
+			Filename:    file.Filename,
+			URL:         file.URL,
+			ContentType: attachmentContentType(file),
+			Size:        file.Size,
+		}
+		if err := validateAttachment(state, attachment); err != nil {
+			rejected = append(rejected, err.Error())
+			continue
+		}
+		if attachment.IsText() {
+			text, err := downloadText(ctx, attachment.URL, maxInlineText)
+			if err != nil {
+				log.Printf("Failed to download attachment %s, linking it instead: %v", attachment.Filename, err)
+			}
+			attachment.Text = text
+		}
+		if attachment.Text == "" {
+			if err := hostAttachment(ctx, &attachment, state.AttachmentLimits.MaxBytes(), i.ID); err != nil {
+				log.Printf("Failed to commit attachment %s, linking it from Discord instead: %v", attachment.Filename, err)
+			}
+		}
+		state.Attachments = append(state.Attachments, attachment)
+		accepted = append(accepted, attachment)
+	}
+
+	_, exists := updateProgress(stateKey, func(state *ModalState) {
+		for _, attachment := range accepted {
+			if err := validateAttachment(state, attachment); err != nil {
+				rejected = append(rejected, err.Error())
+				continue
+			}
+			state.Attachments = append(state.Attachments, attachment)
+			added = append(added, attachment.Filename)
+		}
+	})
+	if !exists {
+		editDeferredResponse(s, i, "❌ Your report expired before the files were attached. Use /drafts to pick it up again.")
+		return
+	}
+
+	var content strings.Builder
+	if len(added) > 0 {
+		content.WriteString(fmt.Sprintf("📎 Attached %s. Files are added to your report when you submit it.",
+			strings.Join(added, ", ")))
+	}
+	for _, reason := range rejected {
+		content.WriteString(fmt.Sprintf("\n⚠️ %s", reason))
+	}
+	editDeferredResponse(s, i, strings.TrimSpace(content.String()))
+}
+
+// hostAttachment commits a file to the attachments repository and links it
+// from there, so the issue doesn't depend on Discord's link. Files stay
+// linked from Discord when no repository is configured.
+func hostAttachment(ctx context.Context, attachment *Attachment, limit int, id string) error {
+	if attachmentRepo == nil || GithubClient == nil {
+		return nil
+	}
+
+	data, err := download(ctx, attachment.URL, limit)
+	if err != nil {
+		return err
+	}
+	if len(data) > limit {
+		return fmt.Errorf("file is larger than %d bytes", limit)
+	}
+
+	path := attachmentPath(time.Now(), id, attachment.Filename)
+	link, err := GithubClient.CommitFile(ctx, attachmentRepo.Owner, attachmentRepo.Repo, path,
+		fmt.Sprintf("Add %s from Discord", attachment.Filename), data)
+	if err != nil {
+		return err
+	}
+	attachment.URL = link
+	attachment.Hosted = true
+	return nil
+}
+
+// attachmentPath is where a file is committed in the attachments repository,
+// grouped by month and made unique by the interaction that attached it
+func attachmentPath(now time.Time, id, filename string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, filename)
+	return fmt.Sprintf("%s/%s-%s", now.UTC().Format("2006/01"), id, safe)
+}
+
+// findAttachableReport returns the user's report in progress in the channel
+// that accepts attachments
+func findAttachableReport(channelID, userID string) (string, *ModalState) {
+	for _, command := range config.GetChannelCommands(channelID) {
+		stateKey := fmt.Sprintf("%s_%s_%s", command, channelID, userID)
+		if state, exists := sessions.Get(stateKey); exists && state.AttachmentLimits != nil {
+			return stateKey, state
+		}
+	}
+	return "", nil
+}
+
+// validateAttachment checks a file against the report's attachment limits
+func validateAttachment(state *ModalState, attachment Attachment) error {
+	limits := state.AttachmentLimits
+	if len(state.Attachments) >= limits.FileLimit() {
+		return fmt.Errorf("%s was not attached, reports can have at most %d files", attachment.Filename, limits.FileLimit())
+	}
+	if attachment.Size > limits.MaxBytes() {
+		return fmt.Errorf("%s was not attached, files can be at most %d MB", attachment.Filename, limits.MaxBytes()/(1024*1024))
+	}
+	if !limits.Allows(attachment.ContentType) {
+		return fmt.Errorf("%s was not attached, %s files are not accepted", attachment.Filename, displayContentType(attachment.ContentType))
+	}
+	return nil
+}
+
+// attachmentContentType returns the content type Discord reported for a
+// file, falling back to its extension. Logs are often uploaded without one.
+func attachmentContentType(file *discordgo.MessageAttachment) string {
+	if file.ContentType != "" {
+		return file.ContentType
+	}
+	extension := strings.ToLower(filepath.Ext(file.Filename))
+	if extension == ".log" {
+		return "text/plain"
+	}
+	return mime.TypeByExtension(extension)
+}
+
+func displayContentType(contentType string) string {
+	if contentType == "" {
+		return "unknown"
+	}
+	return contentType
+}
+
+// handleClearAttachments removes every attachment from a report and shows
+// the review again
+func handleClearAttachments(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	state, exists := updateProgress(stateKey, func(state *ModalState) {
+		state.Attachments = nil
+	})
+	if !exists {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	advanceReport(s, i, state, stateKey)
+}
+
+// attachmentsMarkdown renders a report's attachments for GitHub. Text files
+// downloaded when they were attached are inlined in collapsible blocks, other
+// files are linked from the attachments repository or from Discord.
+func attachmentsMarkdown(attachments []Attachment) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("### Attachments\n")
+
+	budget := maxInlineText
+	linked := false
+	for _, attachment := range attachments {
+		if attachment.Text != "" && budget > 0 {
+			text := truncateInlineText(attachment.Text, budget)
+			budget -= len(text)
+			content.WriteString(fmt.Sprintf("\n<details><summary>%s</summary>\n\n%s\n\n</details>\n",
+				attachment.Filename, codeBlock(text)))
+			continue
+		}
+
+		linked = linked || !attachment.Hosted
+		if strings.HasPrefix(attachment.ContentType, "image/") {
+			content.WriteString(fmt.Sprintf("\n![%s](%s)\n", attachment.Filename, attachment.URL))
+		} else {
+			content.WriteString(fmt.Sprintf("\n- [%s](%s)\n", attachment.Filename, attachment.URL))
+		}
+	}
+
+	if linked {
+		content.WriteString("\n_Linked files are hosted on Discord and stop loading after about a day._")
+	}
+	return content.String()
+}
+
+// hasLinkedAttachments reports whether any attachment is linked from Discord,
+// so it will disappear once Discord's link expires
+func hasLinkedAttachments(attachments []Attachment) bool {
+	for _, attachment := range attachments {
+		if attachment.Text == "" && !attachment.Hosted {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateInlineText cuts text to at most limit bytes, noting that it was cut short
+func truncateInlineText(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return strings.ToValidUTF8(text[:limit], "") + "\n... (truncated)"
+}
+
+// downloadText downloads up to limit bytes of a text attachment, noting
+// when the file was cut short
+func downloadText(ctx context.Context, url string, limit int) (string, error) {
+	data, err := download(ctx, url, limit)
+	if err != nil {
+		return "", err
+	}
+	return truncateInlineText(string(data), limit), nil
+}
+
+// download reads up to limit+1 bytes of an attachment, so callers can tell
+// when it's larger than limit
+func download(ctx context.Context, url string, limit int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := attachmentHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, int64(limit)+1))
+}
+
+// codeBlock wraps text in a fence longer than any run of backticks it contains
+func codeBlock(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s\n%s\n%s", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+// respondEphemeral responds with a plain ephemeral message
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+)
+
+func TestValidateAttachment(t *testing.T) {
+	state := &ModalState{
+		AttachmentLimits: &config.AttachmentConfig{MaxFiles: 2, MaxSizeMB: 1},
+		Attachments:      []Attachment{{Filename: "first.png"}},
+	}
+
+	tests := []struct {
+		name       string
+		attachment Attachment
+		wantErr    string
+	}{
+		{"accepted", Attachment{Filename: "screen.png", ContentType: "image/png", Size: 1024}, ""},
+		{"too large", Attachment{Filename: "big.png", ContentType: "image/png", Size: 2 * 1024 * 1024}, "at most 1 MB"},
+		{"type not allowed", Attachment{Filename: "fw.zip", ContentType: "application/zip", Size: 10}, "application/zip files are not accepted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttachment(state, tt.attachment)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateAttachment() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateAttachment() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	state.Attachments = append(state.Attachments, Attachment{Filename: "second.png"})
+	if err := validateAttachment(state, tests[0].attachment); err == nil || !strings.Contains(err.Error(), "at most 2 files") {
+		t.Errorf("validateAttachment() over the file limit error = %v", err)
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	if result := codeBlock("line\n"); result != "```\nline\n```" {
+		t.Errorf("codeBlock() = %q", result)
+	}
+	if result := codeBlock("has ``` inside"); result != "````\nhas ``` inside\n````" {
+		t.Errorf("codeBlock() with backticks = %q", result)
+	}
+}
+
+func TestAttachmentsMarkdown(t *testing.T) {
+	markdown := attachmentsMarkdown([]Attachment{
+		{Filename: "device.log", URL: "https://cdn.example/device.log", ContentType: "text/plain", Text: "DEBUG | booting\n"},
+		{Filename: "screen.png", URL: "https://cdn.example/screen.png", ContentType: "image/png"},
+		{Filename: "missing.log", URL: "https://cdn.example/missing.log", ContentType: "text/plain"},
+	})
+
+	expected := []string{
+		"<details><summary>device.log</summary>\n\n```\nDEBUG | booting\n```\n\n</details>",
+		"![screen.png](https://cdn.example/screen.png)",
+		"- [missing.log](https://cdn.example/missing.log)",
+		"stop loading after about a day",
+	}
+	for _, part := range expected {
+		if !strings.Contains(markdown, part) {
+			t.Errorf("attachmentsMarkdown() missing %q in:\n%s", part, markdown)
+		}
+	}
+
+	inlined := attachmentsMarkdown([]Attachment{{Filename: "device.log", ContentType: "text/plain", Text: "DEBUG | booting\n"}})
+	if strings.Contains(inlined, "Discord") {
+		t.Errorf("attachmentsMarkdown() with only inlined files mentions Discord's links:\n%s", inlined)
+	}
+
+	hosted := attachmentsMarkdown([]Attachment{{Filename: "screen.png", URL: "https://github.com/meshtastic/bot-attachments/blob/main/screen.png?raw=true", ContentType: "image/png", Hosted: true}})
+	if !strings.Contains(hosted, "![screen.png](https://github.com/meshtastic/bot-attachments/blob/main/screen.png?raw=true)") || strings.Contains(hosted, "Discord") {
+		t.Errorf("attachmentsMarkdown() with a committed file should link it without mentioning Discord:\n%s", hosted)
+	}
+
+	if attachmentsMarkdown(nil) != "" {
+		t.Error("attachmentsMarkdown() without attachments should be empty")
+	}
+}
+
+func TestDownloadText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.log" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("DEBUG | booting\n"))
+	}))
+	defer server.Close()
+
+	text, err := downloadText(context.Background(), server.URL+"/device.log", 100)
+	if err != nil || text != "DEBUG | booting\n" {
+		t.Errorf("downloadText() = %q, %v, want the file", text, err)
+	}
+	if text, _ := downloadText(context.Background(), server.URL+"/device.log", 5); text != "DEBUG\n... (truncated)" {
+		t.Errorf("downloadText() with a small limit = %q, want it truncated", text)
+	}
+	if _, err := downloadText(context.Background(), server.URL+"/missing.log", 100); err == nil {
+		t.Error("downloadText() of a missing file succeeded, want an error")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := downloadText(ctx, server.URL+"/device.log", 100); err == nil {
+		t.Error("downloadText() with a cancelled context succeeded, want an error")
+	}
+}
+
+func TestAttachmentPath(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	if got, want := attachmentPath(now, "1234", "my screen (1).png"), "2026/03/1234-my-screen--1-.png"; got != want {
+		t.Errorf("attachmentPath() = %q, want %q", got, want)
+	}
+	if got, want := attachmentPath(now, "1234", "../.env"), "2026/03/1234-..-.env"; got != want {
+		t.Errorf("attachmentPath() = %q, want %q", got, want)
+	}
+}
+
+func TestHostAttachment(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("png"))
+	}))
+	defer cdn.Close()
+
+	var committed string
+	useTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		committed = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"content": {"html_url": "https://github.com/meshtastic/bot-attachments/blob/main/screen.png"}}`))
+	})
+
+	attachment := Attachment{Filename: "screen.png", URL: cdn.URL + "/screen.png", ContentType: "image/png", Size: 3}
+	if err := hostAttachment(context.Background(), &attachment, 100, "1234"); err != nil || attachment.Hosted {
+		t.Fatalf("hostAttachment() without a repository = %v, hosted %v, want the Discord link kept", err, attachment.Hosted)
+	}
+
+	InitializeAttachments(&config.Repository{Owner: "meshtastic", Repo: "bot-attachments"})
+	defer InitializeAttachments(nil)
+
+	if err := hostAttachment(context.Background(), &attachment, 2, "1234"); err == nil || attachment.Hosted {
+		t.Errorf("hostAttachment() of a file over the limit = %v, want an error", err)
+	}
+
+	if err := hostAttachment(context.Background(), &attachment, 100, "1234"); err != nil {
+		t.Fatalf("hostAttachment() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(committed, "/repos/meshtastic/bot-attachments/contents/") || !strings.HasSuffix(committed, "/1234-screen.png") {
+		t.Errorf("hostAttachment() committed to %q, want the attachments repository", committed)
+	}
+	if !attachment.Hosted || attachment.URL != "https://github.com/meshtastic/bot-attachments/blob/main/screen.png?raw=true" {
+		t.Errorf("hostAttachment() = %+v, want it linked from the repository", attachment)
+	}
+	if hasLinkedAttachments([]Attachment{attachment}) {
+		t.Error("hasLinkedAttachments() with a committed file = true, want false")
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+// Draft is an unfinished report a user can resume with /drafts
+type Draft struct {
+	ID      string      `json:"id"`
+	UserID  string      `json:"user_id"`
+	State   *ModalState `json:"state"`
+	SavedAt time.Time   `json:"saved_at"`
+}
+
+// DraftStore keeps one draft per user, command and target repo. Drafts are
+// removed once they're older than the retention period.
+type DraftStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	drafts    map[string]*Draft
+	// persist is called with the lock held after every change
+	persist func(map[string]*Draft)
+}
+
+func NewDraftStore(retention time.Duration) *DraftStore {
+	return &DraftStore{
+		retention: retention,
+		drafts:    make(map[string]*Draft),
+	}
+}
+
+// NewFileDraftStore returns a DraftStore that is written to a JSON file on
+// every change
+func NewFileDraftStore(path string, retention time.Duration) (*DraftStore, error) {
+	draftStore := NewDraftStore(retention)
+	if err := store.ReadJSON(path, &draftStore.drafts); err != nil {
+		return nil, err
+	}
+	if draftStore.drafts == nil {
+		draftStore.drafts = make(map[string]*Draft)
+	}
+
+	draftStore.persist = func(drafts map[string]*Draft) {
+		if err := store.WriteJSON(path, drafts); err != nil {
+			log.Printf("Failed to save drafts: %v", err)
+		}
+	}
+
+	log.Printf("Loaded %d drafts from %s", len(draftStore.drafts), path)
+	return draftStore, nil
+}
+
+// draftID identifies a draft by the command and repo it was started for,
+// short enough to fit in a button's CustomID
+func draftID(state *ModalState) string {
+	sum := sha256.Sum256([]byte(state.Command + "\x00" + state.Owner + "/" + state.Repo))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func draftKey(userID, id string) string {
+	return userID + "_" + id
+}
+
+// Save stores the state as the user's draft for its command and repo,
+// replacing any earlier draft. Reports with no answers yet are not saved, and
+// false is returned.
+func (d *DraftStore) Save(state *ModalState) bool {
+	if state.UserID == "" || (len(state.SubmittedValues) == 0 && len(state.Selections) == 0) {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id := draftID(state)
+	d.drafts[draftKey(state.UserID, id)] = &Draft{
+		ID:      id,
+		UserID:  state.UserID,
+		State:   state.clone(),
+		SavedAt: time.Now(),
+	}
+	d.changed()
+	return true
+}
+
+// Get returns a copy of one of the user's drafts
+func (d *DraftStore) Get(userID, id string) (*Draft, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	draft, ok := d.drafts[draftKey(userID, id)]
+	if !ok || d.expired(draft) {
+		return nil, false
+	}
+	return draft.clone(), true
+}
+
+// List returns copies of the user's drafts, newest first
+func (d *DraftStore) List(userID string) []*Draft {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	drafts := make([]*Draft, 0)
+	for _, draft := range d.drafts {
+		if draft.UserID == userID && !d.expired(draft) {
+			drafts = append(drafts, draft.clone())
+		}
+	}
+	sort.Slice(drafts, func(a, b int) bool {
+		return drafts[a].SavedAt.After(drafts[b].SavedAt)
+	})
+	return drafts
+}
+
+// Delete removes the user's draft for the state's command and repo
+func (d *DraftStore) Delete(userID, id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := draftKey(userID, id)
+	if _, ok := d.drafts[key]; !ok {
+		return
+	}
+	delete(d.drafts, key)
+	d.changed()
+}
+
+// EvictExpired removes drafts older than the retention period and returns
+// how many were removed
+func (d *DraftStore) EvictExpired() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	evicted := 0
+	for key, draft := range d.drafts {
+		if d.expired(draft) {
+			delete(d.drafts, key)
+			evicted++
+		}
+	}
+
+	if evicted > 0 {
+		d.changed()
+	}
+	return evicted
+}
+
+func (d *DraftStore) expired(draft *Draft) bool {
+	return time.Since(draft.SavedAt) > d.retention
+}
+
+func (d *DraftStore) changed() {
+	if d.persist != nil {
+		d.persist(d.drafts)
+	}
+}
+
+func (d *Draft) clone() *Draft {
+	clone := *d
+	clone.State = d.State.clone()
+	return &clone
+}
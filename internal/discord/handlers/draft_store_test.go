@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestDraftState(userID, command, repo string) *ModalState {
+	state := newTestState()
+	state.UserID = userID
+	state.Command = command
+	state.Owner = "meshtastic"
+	state.Repo = repo
+	return state
+}
+
+func TestDraftStore_OnePerCommandAndRepo(t *testing.T) {
+	draftStore := NewDraftStore(time.Hour)
+
+	first := newTestDraftState("user1", "bug", "firmware")
+	draftStore.Save(first)
+	updated := newTestDraftState("user1", "bug", "firmware")
+	updated.Step = 3
+	draftStore.Save(updated)
+	draftStore.Save(newTestDraftState("user1", "bug", "web"))
+	draftStore.Save(newTestDraftState("user2", "bug", "firmware"))
+
+	userDrafts := draftStore.List("user1")
+	if len(userDrafts) != 2 {
+		t.Fatalf("List() returned %d drafts, want 2", len(userDrafts))
+	}
+	if userDrafts[0].State.Repo != "web" {
+		t.Errorf("List() first draft is for %s, want the newest (web)", userDrafts[0].State.Repo)
+	}
+
+	draft, ok := draftStore.Get("user1", draftID(first))
+	if !ok || draft.State.Step != 3 {
+		t.Errorf("Get() = %+v, want the updated draft", draft)
+	}
+	if _, ok := draftStore.Get("user2", draftID(newTestDraftState("user2", "bug", "web"))); ok {
+		t.Error("Get() returned another user's draft")
+	}
+
+	draftStore.Delete("user1", draftID(first))
+	if _, ok := draftStore.Get("user1", draftID(first)); ok {
+		t.Error("Get() after Delete() returned a draft")
+	}
+}
+
+func TestDraftStore_SkipsEmptyReports(t *testing.T) {
+	draftStore := NewDraftStore(time.Hour)
+
+	state := newTestDraftState("user1", "bug", "firmware")
+	state.SubmittedValues = map[string]string{}
+	state.Selections = map[string][]string{}
+	draftStore.Save(state)
+
+	noUser := newTestDraftState("", "bug", "firmware")
+	draftStore.Save(noUser)
+
+	if drafts := draftStore.List("user1"); len(drafts) != 0 {
+		t.Errorf("List() = %d drafts, want none for a report with no answers", len(drafts))
+	}
+	if drafts := draftStore.List(""); len(drafts) != 0 {
+		t.Errorf("List() = %d drafts, want none for a report with no user", len(drafts))
+	}
+}
+
+func TestDraftStore_Retention(t *testing.T) {
+	draftStore := NewDraftStore(-time.Second)
+	state := newTestDraftState("user1", "bug", "firmware")
+	draftStore.Save(state)
+
+	if _, ok := draftStore.Get("user1", draftID(state)); ok {
+		t.Error("Get() returned an expired draft")
+	}
+	if evicted := draftStore.EvictExpired(); evicted != 1 {
+		t.Errorf("EvictExpired() = %d, want 1", evicted)
+	}
+}
+
+func TestFileDraftStore_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drafts.json")
+
+	draftStore, err := NewFileDraftStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("NewFileDraftStore() unexpected error: %v", err)
+	}
+	state := newTestDraftState("user1", "bug", "firmware")
+	state.Step = 1
+	draftStore.Save(state)
+
+	reloaded, err := NewFileDraftStore(path, time.Hour)
+	if err != nil {
+		t.Fatalf("NewFileDraftStore() reload unexpected error: %v", err)
+	}
+
+	draft, ok := reloaded.Get("user1", draftID(state))
+	if !ok {
+		t.Fatal("Get() after reload returned no draft")
+	}
+	if draft.State.Step != 1 || draft.State.SubmittedValues["description"] != "It crashes" {
+		t.Errorf("Get() after reload = %+v, want the saved draft", draft.State)
+	}
+}
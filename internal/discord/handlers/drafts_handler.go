@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxListedDrafts is the number of drafts shown by /drafts, one action row each
+const maxListedDrafts = 5
+
+// handleDrafts lists the user's unfinished reports
+func handleDrafts(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	showDrafts(s, i, "")
+}
+
+// showDrafts responds with the user's drafts and a Resume and Discard button
+// for each of them
+func showDrafts(s *discordgo.Session, i *discordgo.InteractionCreate, notice string) {
+	userDrafts := drafts.List(i.Member.User.ID)
+
+	var content strings.Builder
+	if notice != "" {
+		content.WriteString(notice + "\n\n")
+	}
+
+	if len(userDrafts) == 0 {
+		content.WriteString("You don't have any unfinished reports.")
+		err := respondEphemeralMessage(s, i, &discordgo.InteractionResponseData{
+			Content:    content.String(),
+			Components: []discordgo.MessageComponent{},
+		})
+		if err != nil {
+			log.Printf("Error responding with drafts: %v", err)
+		}
+		return
+	}
+
+	if len(userDrafts) > maxListedDrafts {
+		userDrafts = userDrafts[:maxListedDrafts]
+	}
+
+	content.WriteString("**Your unfinished reports**\n")
+	components := make([]discordgo.MessageComponent, 0, len(userDrafts))
+	for index, draft := range userDrafts {
+		content.WriteString(fmt.Sprintf("\n**%d.** %s\n", index+1, describeDraft(draft)))
+
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    fmt.Sprintf("Resume %d", index+1),
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("draftresume_%s", draft.ID),
+				},
+				discordgo.Button{
+					Label:    fmt.Sprintf("Discard %d", index+1),
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("draftdiscard_%s", draft.ID),
+				},
+			},
+		})
+	}
+
+	err := respondEphemeralMessage(s, i, &discordgo.InteractionResponseData{
+		Content:    content.String(),
+		Components: components,
+	})
+	if err != nil {
+		log.Printf("Error responding with drafts: %v", err)
+	}
+}
+
+// describeDraft summarises a draft for the /drafts list
+func describeDraft(draft *Draft) string {
+	state := draft.State
+
+	progress := "ready to review"
+	if steps := planSteps(state.AllFields); !state.Reviewing && state.Step < len(steps) {
+		progress = fmt.Sprintf("part %d of %d", state.Step+1, len(steps))
+	}
+
+	return fmt.Sprintf("/%s %s in %s/%s - %s, saved <t:%d:R>",
+		state.Command, truncateText(state.Title, 80), state.Owner, state.Repo, progress, draft.SavedAt.Unix())
+}
+
+// handleDraftResume restores a draft as the user's active report and shows
+// the step they stopped at, prefilled with their answers
+func handleDraftResume(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	draft, exists := drafts.Get(i.Member.User.ID, id)
+	if !exists {
+		showDrafts(s, i, "⚠️ That draft no longer exists.")
+		return
+	}
+
+	state := draft.State
+	stateKey := fmt.Sprintf("%s_%s_%s", state.Command, state.ChannelID, state.UserID)
+	sessions.Set(stateKey, state)
+	advanceReport(s, i, state, stateKey)
+}
+
+// handleDraftDiscard deletes a draft and shows the remaining ones
+func handleDraftDiscard(s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	drafts.Delete(i.Member.User.ID, id)
+	showDrafts(s, i, "🗑️ Draft discarded.")
+}
+
+// updateProgress changes the report's session and keeps a draft of the
+// answers so far, so they can be resumed with /drafts if the report is
+// abandoned
+func updateProgress(stateKey string, change func(*ModalState)) (*ModalState, bool) {
+	state, exists := sessions.Update(stateKey, change)
+	if exists {
+		drafts.Save(state)
+	}
+	return state, exists
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxDuplicateCandidates is the number of similar issues shown, one button each
+	maxDuplicateCandidates = 5
+	// maxSearchTerms keeps the query within GitHub's limit of five OR operators
+	maxSearchTerms = 6
+)
+
+// duplicateSearchTerms returns the keywords of the report's title, then of
+// its text answers such as the description. Issues matching any of them are
+// candidates, those matching the most first, so short titles still find some.
+func duplicateSearchTerms(state *ModalState) []string {
+	texts := []string{state.Title}
+	for _, field := range state.AllFields {
+		if !field.IsSelect() {
+			texts = append(texts, state.SubmittedValues[field.CustomID])
+		}
+	}
+	return github.SearchTerms(maxSearchTerms, texts...)
+}
+
+// findDuplicates searches the target repo for open issues similar to the
+// report's title and text answers. Search failures are logged and treated as
+// no matches so they never block a report.
+func findDuplicates(ctx context.Context, state *ModalState) []*github.IssueSummary {
+	terms := duplicateSearchTerms(state)
+	candidates, err := issueTracker(state.Tracker).SearchOpenIssues(ctx, state.Owner, state.Repo, terms, maxDuplicateCandidates)
+	if err != nil {
+		log.Printf("Failed to search for duplicate issues: %v", err)
+		return nil
+	}
+	return candidates
+}
+
+// showDuplicates replaces the deferred response with the similar issues and
+// buttons to add the report to one of them or file it anyway
+func showDuplicates(s *discordgo.Session, i *discordgo.InteractionCreate, candidates []*github.IssueSummary, stateKey string) {
+	content := buildDuplicatesMessage(candidates)
+
+	candidateButtons := make([]discordgo.MessageComponent, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidateButtons = append(candidateButtons, discordgo.Button{
+			Label:    fmt.Sprintf("This is my problem: #%d", candidate.Number),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("dupe_%d_%s", candidate.Number, stateKey),
+		})
+	}
+
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: candidateButtons},
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "File a new issue anyway",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("filenew_%s", stateKey),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.DangerButton,
+					CustomID: fmt.Sprintf("cancel_%s", stateKey),
+				},
+			},
+		},
+	}
+	embeds := []*discordgo.MessageEmbed{}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error showing duplicate issues: %v", err)
+	}
+}
+
+// buildDuplicatesMessage lists the similar issues. Links are wrapped in <>
+// so Discord doesn't add a preview for each of them.
+func buildDuplicatesMessage(candidates []*github.IssueSummary) string {
+	var content strings.Builder
+	content.WriteString("🔎 These open issues look similar to your report. If one of them describes your problem, " +
+		"click its button and your report will be added to it as a comment instead.\n")
+
+	for index, candidate := range candidates {
+		content.WriteString(fmt.Sprintf("\n**%d.** [#%d %s](<%s>)",
+			index+1, candidate.Number, truncateText(candidate.Title, 100), candidate.HTMLURL))
+		if candidate.Comments == 1 {
+			content.WriteString(" (1 comment)")
+		} else if candidate.Comments > 1 {
+			content.WriteString(fmt.Sprintf(" (%d comments)", candidate.Comments))
+		}
+	}
+	return content.String()
+}
+
+// handleDuplicate adds the report to an existing issue as a comment. The
+// issue is recorded in the session first, so repeated clicks comment once,
+// and only while reviewing, which handleFileNew clears when it files the
+// report as a new issue.
+// CustomID format: "dupe_<issueNumber>_<stateKey>"
+func handleDuplicate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+
+	parts := strings.SplitN(strings.TrimPrefix(customID, "dupe_"), "_", 2)
+	if len(parts) != 2 {
+		log.Printf("Invalid duplicate button CustomID format: %s", customID)
+		return
+	}
+	number, err := strconv.Atoi(parts[0])
+	stateKey := parts[1]
+
+	if err != nil {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	previous := 0
+	state, exists := sessions.Update(stateKey, func(state *ModalState) {
+		if previous = state.DuplicateOf; state.Reviewing && previous == 0 {
+			state.DuplicateOf = number
+		}
+	})
+	if !exists || !state.Reviewing {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	if previous != 0 {
+		respondEphemeral(s, i, fmt.Sprintf("⏳ Your report is already being added to #%d.", previous))
+		return
+	}
+
+	if err := deferEphemeralResponse(s, i); err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+
+	body := state.issueBody(i.Member.User.Username, i.Member.User.ID)
+	comment := fmt.Sprintf("**Also reported from Discord:** %s\n\n%s", state.Title, body)
+	if len(state.Attachments) > 0 {
+		comment += "\n\n" + attachmentsMarkdown(state.Attachments)
+	}
+
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+	commentURL, err := issueTracker(state.Tracker).CreateComment(ctx, state.Owner, state.Repo, number, comment)
+	if err != nil {
+		log.Printf("Failed to comment on %s issue #%d: %v", state.Tracker.Name(), number, err)
+		state.DuplicateOf = 0
+		drafts.Save(state)
+		editDeferredResponse(s, i, fmt.Sprintf("❌ Failed to add your report to #%d. "+
+			"Your answers were saved, use /drafts to try again later.", number))
+		sessions.Delete(stateKey)
+		return
+	}
+
+	editDeferredResponse(s, i, fmt.Sprintf("👍 Your report was added to #%d. Follow the issue for updates:\n%s",
+		number, commentURL))
+
+	sessions.Delete(stateKey)
+	drafts.Delete(state.UserID, draftID(state))
+}
+
+// handleFileNew creates the issue after the user decided none of the similar
+// issues match their problem. Reviewing is cleared in the same update that
+// checks no duplicate vote is in progress, so the report can't be both added
+// to an issue and filed as a new one.
+func handleFileNew(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	claimed, duplicateOf := false, 0
+	state, exists := sessions.Update(stateKey, func(state *ModalState) {
+		duplicateOf = state.DuplicateOf
+		if claimed = state.Reviewing && duplicateOf == 0; claimed {
+			state.Reviewing = false
+		}
+	})
+	if exists && duplicateOf != 0 {
+		respondEphemeral(s, i, fmt.Sprintf("⏳ Your report is already being added to #%d.", duplicateOf))
+		return
+	}
+	if !exists || !claimed {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+
+	if err := deferEphemeralResponse(s, i); err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+	createIssueFromState(s, i, state, stateKey)
+}
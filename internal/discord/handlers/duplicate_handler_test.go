@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+func TestBuildDuplicatesMessage(t *testing.T) {
+	candidates := []*github.IssueSummary{
+		{Number: 12, Title: "Crash when pairing", HTMLURL: "https://github.com/meshtastic/firmware/issues/12", Comments: 3},
+		{Number: 40, Title: "Pairing fails", HTMLURL: "https://github.com/meshtastic/firmware/issues/40", Comments: 1},
+		{Number: 41, Title: "Pairing is slow", HTMLURL: "https://github.com/meshtastic/firmware/issues/41"},
+	}
+
+	message := buildDuplicatesMessage(candidates)
+
+	expectedLines := []string{
+		"**1.** [#12 Crash when pairing](<https://github.com/meshtastic/firmware/issues/12>) (3 comments)",
+		"**2.** [#40 Pairing fails](<https://github.com/meshtastic/firmware/issues/40>) (1 comment)",
+		"**3.** [#41 Pairing is slow](<https://github.com/meshtastic/firmware/issues/41>)",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(message, line+"\n") && !strings.HasSuffix(message, line) {
+			t.Errorf("buildDuplicatesMessage() missing line %q in:\n%s", line, message)
+		}
+	}
+}
+
+func TestDuplicateSearchTerms(t *testing.T) {
+	tests := []struct {
+		name   string
+		title  string
+		answer string
+		want   []string
+	}{
+		{name: "title first", title: "[Bug]: Crash when pairing over Bluetooth", answer: "radio reboots", want: []string{"crash", "pairing", "over", "bluetooth", "radio", "reboots"}},
+		{name: "common words skipped", title: "Bluetooth pairing fails on the app", want: []string{"bluetooth", "pairing", "fails"}},
+		{name: "short title", title: "[Bug]: Crash", answer: "bluetooth pairing fails", want: []string{"crash", "bluetooth", "pairing", "fails"}},
+		{name: "limited", title: "Crash pairing bluetooth", answer: "radio reboots every hour", want: []string{"crash", "pairing", "bluetooth", "radio", "reboots", "every"}},
+		{name: "select answers skipped", title: "Crash", want: []string{"crash"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestState()
+			state.Title = tt.title
+			state.AllFields = []config.FieldConfig{
+				{CustomID: "description", Label: "Description"},
+				{CustomID: "platform", Label: "Platform", Type: "dropdown", Options: []string{"Android"}},
+			}
+			state.SubmittedValues = map[string]string{"description": tt.answer, "platform": "Android"}
+
+			if got := duplicateSearchTerms(state); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("duplicateSearchTerms() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
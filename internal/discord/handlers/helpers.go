@@ -2,22 +2,38 @@ package handlers
 
 import (
 	"fmt"
+	"log"
 	"strings"
 
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
 	"github.com/bwmarrin/discordgo"
 )
 
-// buildIssueBody constructs the issue body from submitted values
-func buildIssueBody(submittedValues map[string]string, username, userID string) string {
-	var body strings.Builder
+// buildIssueBody renders the submitted values in template order using the
+// same format as GitHub's issue forms, so issue-form parsers can read it.
+// Values are keyed by field CustomID.
+func buildIssueBody(fields []config.FieldConfig, values map[string]string, username, userID string) string {
+	sections := make([]string, 0, len(fields)+1)
+	sections = append(sections, fmt.Sprintf("Submitted via Discord by: %s (%s)", username, userID))
 
-	for label, value := range submittedValues {
-		body.WriteString(fmt.Sprintf("### %s\n%s\n\n", label, value))
+	for _, field := range fields {
+		sections = append(sections, fmt.Sprintf("### %s\n\n%s", field.Label, formatFieldValue(field, values[field.CustomID])))
 	}
 
-	body.WriteString(fmt.Sprintf("\n---\nSubmitted via Discord by: %s (%s)", username, userID))
+	return strings.Join(sections, "\n\n")
+}
 
-	return body.String()
+// formatFieldValue renders one answer the way GitHub's issue forms do
+func formatFieldValue(field config.FieldConfig, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "_No response_"
+	}
+	if field.Render != "" {
+		return fmt.Sprintf("```%s\n%s\n```", field.Render, value)
+	}
+	return value
 }
 
 // truncatePlaceholder truncates placeholder text to 100 chars
@@ -28,6 +44,135 @@ func truncatePlaceholder(text string) string {
 	return text
 }
 
+// truncateModalTitle truncates a modal title to Discord's 45 character limit
+func truncateModalTitle(text string) string {
+	return truncateText(text, 45)
+}
+
+// truncateText truncates text to max characters, ending in an ellipsis when shortened
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) > max {
+		return string(runes[:max-3]) + "..."
+	}
+	return text
+}
+
+// commandOptionString returns the value of a string option on a slash command,
+// or an empty string if the option was not provided
+func commandOptionString(i *discordgo.InteractionCreate, name string) string {
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == name && option.Type == discordgo.ApplicationCommandOptionString {
+			return strings.TrimSpace(option.StringValue())
+		}
+	}
+	return ""
+}
+
+// buildModal builds a Discord modal with a text input for each field,
+// prefilled with the user's earlier answers or the field's default value
+func buildModal(customID, title string, fields []config.FieldConfig, values map[string]string) *discordgo.InteractionResponseData {
+	components := make([]discordgo.MessageComponent, 0, len(fields))
+	for _, field := range fields {
+		style := discordgo.TextInputShort
+		if field.Style == "paragraph" {
+			style = discordgo.TextInputParagraph
+		}
+
+		value := field.Value
+		if submitted, ok := values[field.CustomID]; ok {
+			value = submitted
+		}
+
+		textInput := discordgo.TextInput{
+			CustomID:    field.CustomID,
+			Label:       field.Label,
+			Style:       style,
+			Placeholder: truncatePlaceholder(field.Placeholder),
+			Value:       value,
+			Required:    field.Required,
+		}
+
+		if field.MinLength > 0 {
+			textInput.MinLength = field.MinLength
+		}
+		if field.MaxLength > 0 {
+			textInput.MaxLength = field.MaxLength
+		}
+
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{textInput},
+		})
+	}
+
+	return &discordgo.InteractionResponseData{
+		CustomID:   customID,
+		Title:      truncateModalTitle(title),
+		Components: components,
+	}
+}
+
+// updatesMessage reports whether an interaction came from a message the
+// response can replace: a button or select menu, or a modal opened by one
+func updatesMessage(i *discordgo.InteractionCreate) bool {
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		return true
+	case discordgo.InteractionModalSubmit:
+		return i.Message != nil
+	}
+	return false
+}
+
+// respondEphemeralMessage responds with an ephemeral message. Button clicks,
+// select menus and the modals they open update the message they came from
+// instead of adding another, so old buttons aren't left behind.
+func respondEphemeralMessage(s *discordgo.Session, i *discordgo.InteractionCreate, data *discordgo.InteractionResponseData) error {
+	responseType := discordgo.InteractionResponseChannelMessageWithSource
+	if updatesMessage(i) {
+		responseType = discordgo.InteractionResponseUpdateMessage
+	}
+
+	data.Flags |= discordgo.MessageFlagsEphemeral
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: data,
+	})
+}
+
+// deferEphemeralResponse acknowledges an interaction straight away so slow
+// work doesn't run past Discord's 3 second deadline. Interactions from a
+// message update it, see updatesMessage, everything else gets a new ephemeral
+// message. The final content is sent with editDeferredResponse.
+func deferEphemeralResponse(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	responseType := discordgo.InteractionResponseDeferredChannelMessageWithSource
+	if updatesMessage(i) {
+		responseType = discordgo.InteractionResponseDeferredMessageUpdate
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// editDeferredResponse replaces a deferred response with the final message,
+// removing any buttons, select menus or embeds left on it
+func editDeferredResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	components := []discordgo.MessageComponent{}
+	embeds := []*discordgo.MessageEmbed{}
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Components: &components,
+		Embeds:     &embeds,
+	})
+	if err != nil {
+		log.Printf("Error editing deferred response: %v", err)
+	}
+}
+
 // extractModalFields extracts field values from modal components
 func extractModalFields(components []discordgo.MessageComponent) map[string]string {
 	fields := make(map[string]string)
@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -53,65 +55,123 @@ func TestTruncatePlaceholder(t *testing.T) {
 	}
 }
 
+func TestTruncateModalTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "short title unchanged",
+			input:    "[Bug]: App crashes",
+			expected: "[Bug]: App crashes",
+		},
+		{
+			name:     "exactly 45 chars unchanged",
+			input:    strings.Repeat("a", 45),
+			expected: strings.Repeat("a", 45),
+		},
+		{
+			name:     "long title truncated with ellipsis",
+			input:    strings.Repeat("a", 60),
+			expected: strings.Repeat("a", 42) + "...",
+		},
+		{
+			name:     "multi-byte characters counted as one",
+			input:    strings.Repeat("é", 46),
+			expected: strings.Repeat("é", 42) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := truncateModalTitle(tt.input)
+			if result != tt.expected {
+				t.Errorf("truncateModalTitle(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestBuildIssueBody(t *testing.T) {
+	fields := []config.FieldConfig{
+		{CustomID: "description", Label: "Description", Type: "textarea"},
+		{CustomID: "steps", Label: "Steps", Type: "textarea"},
+		{CustomID: "logs", Label: "Logs", Type: "textarea", Render: "shell"},
+		{CustomID: "version", Label: "Version", Type: "input"},
+	}
+
 	tests := []struct {
 		name            string
+		fields          []config.FieldConfig
 		submittedValues map[string]string
 		username        string
 		userID          string
-		wantContains    []string
+		expected        string
 	}{
 		{
-			name: "single field",
+			name:   "all fields in template order",
+			fields: fields,
 			submittedValues: map[string]string{
-				"Description": "This is a bug",
+				"version":     "2.5.0",
+				"logs":        "ERROR boot failed",
+				"steps":       "1. Do this\n2. Do that",
+				"description": "This is a bug",
+			},
+			username: "john_doe",
+			userID:   "789",
+			expected: "Submitted via Discord by: john_doe (789)\n\n" +
+				"### Description\n\nThis is a bug\n\n" +
+				"### Steps\n\n1. Do this\n2. Do that\n\n" +
+				"### Logs\n\n```shell\nERROR boot failed\n```\n\n" +
+				"### Version\n\n2.5.0",
+		},
+		{
+			name:   "empty answers use no response placeholder",
+			fields: fields,
+			submittedValues: map[string]string{
+				"description": "This is a bug",
+				"steps":       "   ",
 			},
 			username: "testuser",
 			userID:   "123456",
-			wantContains: []string{
-				"### Description",
-				"This is a bug",
-				"Submitted via Discord by: testuser (123456)",
-			},
+			expected: "Submitted via Discord by: testuser (123456)\n\n" +
+				"### Description\n\nThis is a bug\n\n" +
+				"### Steps\n\n_No response_\n\n" +
+				"### Logs\n\n_No response_\n\n" +
+				"### Version\n\n_No response_",
 		},
 		{
-			name: "multiple fields",
-			submittedValues: map[string]string{
-				"Title":       "Bug Title",
-				"Description": "Bug description",
-				"Steps":       "1. Do this\n2. Do that",
+			name: "duplicate labels keep both answers",
+			fields: []config.FieldConfig{
+				{CustomID: "field_0", Label: "Details"},
+				{CustomID: "field_1", Label: "Details"},
 			},
-			username: "john_doe",
-			userID:   "789",
-			wantContains: []string{
-				"### Title",
-				"Bug Title",
-				"### Description",
-				"Bug description",
-				"### Steps",
-				"1. Do this\n2. Do that",
-				"Submitted via Discord by: john_doe (789)",
+			submittedValues: map[string]string{
+				"field_0": "first",
+				"field_1": "second",
 			},
+			username: "testuser",
+			userID:   "123456",
+			expected: "Submitted via Discord by: testuser (123456)\n\n" +
+				"### Details\n\nfirst\n\n" +
+				"### Details\n\nsecond",
 		},
 		{
-			name:            "empty values",
+			name:            "no fields",
+			fields:          []config.FieldConfig{},
 			submittedValues: map[string]string{},
 			username:        "emptyuser",
 			userID:          "000",
-			wantContains: []string{
-				"Submitted via Discord by: emptyuser (000)",
-			},
+			expected:        "Submitted via Discord by: emptyuser (000)",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := buildIssueBody(tt.submittedValues, tt.username, tt.userID)
-
-			for _, want := range tt.wantContains {
-				if !strings.Contains(result, want) {
-					t.Errorf("buildIssueBody() missing expected content:\nwant substring: %q\ngot: %q", want, result)
-				}
+			result := buildIssueBody(tt.fields, tt.submittedValues, tt.username, tt.userID)
+			if result != tt.expected {
+				t.Errorf("buildIssueBody() mismatch:\nwant: %q\ngot:  %q", tt.expected, result)
 			}
 		})
 	}
@@ -210,3 +270,64 @@ func TestExtractModalFields(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildModal_Prefill(t *testing.T) {
+	fields := []config.FieldConfig{
+		{CustomID: "what", Label: "What happened?", Style: "paragraph"},
+		{CustomID: "version", Label: "Version", Style: "short", Value: "2.x"},
+		{CustomID: "steps", Label: "Steps", Style: "paragraph", Value: "1."},
+	}
+	values := map[string]string{
+		"what":  "It crashed",
+		"steps": "",
+	}
+
+	modal := buildModal("modal_continue_key", "Bug Report", fields, values)
+
+	expected := map[string]string{
+		"what":    "It crashed",
+		"version": "2.x",
+		"steps":   "",
+	}
+	for _, row := range modal.Components {
+		input := row.(discordgo.ActionsRow).Components[0].(discordgo.TextInput)
+		if input.Value != expected[input.CustomID] {
+			t.Errorf("%s: Value = %q, want %q", input.CustomID, input.Value, expected[input.CustomID])
+		}
+	}
+}
+
+func TestUpdatesMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *discordgo.Interaction
+		want        bool
+	}{
+		{name: "button", interaction: &discordgo.Interaction{Type: discordgo.InteractionMessageComponent}, want: true},
+		{name: "modal from a button", interaction: &discordgo.Interaction{Type: discordgo.InteractionModalSubmit, Message: &discordgo.Message{ID: "1"}}, want: true},
+		{name: "modal from a command", interaction: &discordgo.Interaction{Type: discordgo.InteractionModalSubmit}},
+		{name: "command", interaction: &discordgo.Interaction{Type: discordgo.InteractionApplicationCommand}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updatesMessage(&discordgo.InteractionCreate{Interaction: tt.interaction}); got != tt.want {
+				t.Errorf("updatesMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavigationRow(t *testing.T) {
+	forward := discordgo.Button{Label: "Continue", CustomID: "continue_bug_1_2"}
+
+	row := navigationRow("bug_1_2", forward, 1)
+	if len(row.Components) != 3 || row.Components[1].(discordgo.Button).CustomID != "back_1_bug_1_2" {
+		t.Errorf("navigationRow() = %+v, want Continue, Back to step 1 and Cancel", row.Components)
+	}
+
+	row = navigationRow("bug_1_2", forward, -1)
+	if len(row.Components) != 2 || row.Components[1].(discordgo.Button).Label != "Cancel" {
+		t.Errorf("navigationRow() = %+v, want Continue and Cancel", row.Components)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	config "github.com/meshtastic/meshtastic-bot/internal/config"
 	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -13,35 +14,143 @@ var (
 	GithubRepo   string
 )
 
-func InitializeGithub(token, owner, repo string) {
-	GithubClient = github.NewClient(token)
+// InitializeGithub sets the GitHub client, authenticated with a token or as
+// a GitHub App, and the default repository
+func InitializeGithub(client *github.Client, owner, repo string) {
+	GithubClient = client
 	GithubOwner = owner
 	GithubRepo = repo
 }
 
 // ModalState tracks the state of multi-part modals
 type ModalState struct {
-	Title           string
-	AllFields       []config.FieldConfig
+	Title     string
+	AllFields []config.FieldConfig
+	// SubmittedValues holds the answers collected so far, keyed by field CustomID
 	SubmittedValues map[string]string
 	Labels          []string
 	Command         string
 	ChannelID       string
+	UserID          string
 	Owner           string
 	Repo            string
+	// Step is the index of the next step to show, see planSteps
+	Step int
+	// Reviewing is set once every step is complete, so edited steps return to the review screen
+	Reviewing bool
+	// Selections holds the options chosen so far in dropdown select menus, keyed by field CustomID
+	Selections map[string][]string
+	// Credit names the author of a Discord message the report was started from
+	Credit string
+	// AttachmentLimits is nil when the report can't have attachments
+	AttachmentLimits *config.AttachmentConfig
+	// Attachments are the files added with /attach
+	Attachments []Attachment
+	// OpenThread opens a companion thread once the issue is created
+	OpenThread bool
+	// Assignees and Milestone are set on the issue along with Labels
+	Assignees []string
+	Milestone string
+	// Project is nil when the issue isn't added to a project board
+	Project *config.ProjectConfig
+	// InteractionID is the ID of the interaction the report was started
+	// with, so submitting it twice files one issue. It's empty for reports
+	// from the legacy modal.
+	InteractionID string
+	// Tracker is where the issue is filed, GitHub when empty
+	Tracker config.Tracker
+	// DuplicateOf is the issue the report is being added to, so clicking
+	// its button twice comments once
+	DuplicateOf int
 }
 
-var modalStates = make(map[string]*ModalState)
+// issueBody renders the report's answers for GitHub, crediting the author of
+// the original message when the report was started from one
+func (m *ModalState) issueBody(username, userID string) string {
+	body := buildIssueBody(m.AllFields, m.SubmittedValues, username, userID)
+	if m.Credit == "" {
+		return body
+	}
+	return m.Credit + "\n\n" + body
+}
+
+// issueRequest is the GitHub issue for the report
+func (m *ModalState) issueRequest(body string) github.IssueRequest {
+	return github.IssueRequest{
+		Title:     m.Title,
+		Body:      body,
+		Labels:    m.Labels,
+		Assignees: m.Assignees,
+		Milestone: m.Milestone,
+	}
+}
+
+// selection returns the options currently chosen for a dropdown field,
+// falling back to the field's default
+func (m *ModalState) selection(field config.FieldConfig) []string {
+	if selected, ok := m.Selections[field.CustomID]; ok {
+		return selected
+	}
+	if field.Default != "" {
+		return []string{field.Default}
+	}
+	return nil
+}
+
+// sessions holds in-progress reports, keyed by "<command>_<channelID>_<userID>"
+var sessions SessionStore = NewMemorySessionStore(config.DefaultSessionTTL)
+
+// InitializeSessions replaces the in-memory session store, e.g. with a file-backed one
+func InitializeSessions(store SessionStore) {
+	sessions = store
+}
+
+// issues records which Discord user reported each issue created by the bot
+var issues = store.NewIssueStore()
+
+// InitializeIssues replaces the in-memory issue records, e.g. with file-backed ones
+func InitializeIssues(issueStore *store.IssueStore) {
+	issues = issueStore
+}
+
+// drafts holds unfinished reports that can be resumed with /drafts
+var drafts = NewDraftStore(config.DefaultDraftRetention)
+
+// InitializeDrafts replaces the in-memory draft store, e.g. with a file-backed one
+func InitializeDrafts(store *DraftStore) {
+	drafts = store
+}
+
+var outbox = NewOutbox()
+
+// InitializeOutbox replaces the in-memory outbox, e.g. with a file-backed one
+func InitializeOutbox(o *Outbox) {
+	outbox = o
+}
 
 var commandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-	"tapsign": handleTapsign,
-	"feature": handleFeature,
-	"faq":     handleFaq,
-	"bug":     handleBug,
+	"tapsign":  handleTapsign,
+	"feature":  handleFeature,
+	"faq":      handleFaq,
+	"bug":      handleBug,
+	"drafts":   handleDrafts,
+	"attach":   handleAttach,
+	"issue":    handleIssue,
+	"myissues": handleMyIssues,
+
+	"Report as bug": handleReportMessage,
 }
 
 // HandleInteraction routes interactions to appropriate handlers
 func HandleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !work.start() {
+		if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+			respondEphemeral(s, i, "🔄 The bot is restarting, please try again in a minute.")
+		}
+		return
+	}
+	defer work.done()
+
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
 		if handler, exists := commandHandlers[i.ApplicationCommandData().Name]; exists {
@@ -77,5 +186,7 @@ func handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch data.Name {
 	case "faq":
 		handleFaqAutocomplete(s, i)
+	case "issue":
+		handleIssueAutocomplete(s, i)
 	}
 }
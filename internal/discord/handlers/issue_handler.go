@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// issueExcerptLines and issueExcerptLength limit the body shown in an issue embed
+	issueExcerptLines  = 6
+	issueExcerptLength = 400
+	// maxLinkedPullRequests is the number of linked pull requests listed
+	maxLinkedPullRequests = 5
+)
+
+// Embed colors matching GitHub's issue states
+const (
+	colorOpen       = 0x1f883d
+	colorCompleted  = 0x8250df
+	colorNotPlanned = 0x59636e
+)
+
+// handleIssue shows an issue or pull request as an embed
+func handleIssue(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if GithubClient == nil {
+		respondEphemeral(s, i, "❌ /issue shows GitHub issues, and no modal files issues on GitHub.")
+		return
+	}
+
+	typed := commandOptionString(i, "issue")
+	ref, err := resolveIssueRef(typed, i.ChannelID)
+	// Repositories are suggested on their own until a number is typed
+	if repoName := strings.TrimSpace(typed); err != nil && strings.Count(repoName, "/") == 1 && !strings.ContainsAny(repoName, "#:") {
+		respondEphemeral(s, i, fmt.Sprintf("❌ Add the issue number to pick an issue, e.g. %s#123.", repoName))
+		return
+	}
+	if err != nil {
+		respondEphemeral(s, i, fmt.Sprintf("❌ %v. Use a number, repo#number or an issue URL.", err))
+		return
+	}
+	if !knownRepository(ref) {
+		respondEphemeral(s, i, fmt.Sprintf("❌ %s/%s isn't one of the repositories this bot shows issues from.", ref.Owner, ref.Repo))
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+	issue, err := GithubClient.GetIssue(ctx, ref.Owner, ref.Repo, ref.Number)
+	if err != nil {
+		log.Printf("Failed to get GitHub issue %s: %v", ref, err)
+		if github.IsNotFound(err) {
+			editDeferredResponse(s, i, fmt.Sprintf("❌ Couldn't find %s.", ref))
+		} else {
+			editDeferredResponse(s, i, fmt.Sprintf("❌ Couldn't reach GitHub to get %s, please try again later.", ref))
+		}
+		return
+	}
+
+	var pulls []*github.IssueSummary
+	if !issue.IsPullRequest {
+		pulls, err = GithubClient.ListLinkedPullRequests(ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			log.Printf("Failed to list pull requests linked to %s: %v", ref, err)
+		}
+	}
+
+	embeds := []*discordgo.MessageEmbed{buildIssueEmbed(ref, issue, pulls)}
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+		log.Printf("Error editing deferred response: %v", err)
+	}
+}
+
+// resolveIssueRef parses an issue reference, filling in the repository when
+// it isn't named: the channel's configured repository for a bare number, and
+// the matching alias or configured repository for "repo#number"
+func resolveIssueRef(text, channelID string) (github.IssueRef, error) {
+	ref, err := github.ParseIssueRef(text, config.GitHubWebHost())
+	if err != nil {
+		return ref, err
+	}
+
+	switch {
+	case ref.Owner == "" && ref.Repo == "":
+		if repository, ok := config.GetChannelRepository(channelID); ok {
+			ref.Owner, ref.Repo = repository.Owner, repository.Repo
+		} else {
+			ref.Owner, ref.Repo = GithubOwner, GithubRepo
+		}
+	case ref.Owner == "":
+		if repository, ok := config.ResolveRepoAlias(ref.Repo); ok {
+			ref.Owner, ref.Repo = repository.Owner, repository.Repo
+		} else {
+			ref.Owner = GithubOwner
+		}
+	}
+	return ref, nil
+}
+
+// knownRepository reports whether an issue may be shown in Discord: it must
+// be in the default repository, a configured one or an unfurl alias's. The
+// bot's token may read private repositories that mustn't leak into chat.
+func knownRepository(ref github.IssueRef) bool {
+	if strings.EqualFold(ref.Owner, GithubOwner) && strings.EqualFold(ref.Repo, GithubRepo) {
+		return true
+	}
+	return config.IsKnownRepository(ref.Owner, ref.Repo)
+}
+
+// buildIssueEmbed shows an issue's state, labels, assignees, milestone,
+// comment count, linked pull requests and the start of its body
+func buildIssueEmbed(ref github.IssueRef, issue *github.IssueDetails, pulls []*github.IssueSummary) *discordgo.MessageEmbed {
+	kind := "Issue"
+	if issue.IsPullRequest {
+		kind = "Pull request"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       truncateText(fmt.Sprintf("#%d %s", issue.Number, issue.Title), 256),
+		URL:         issue.HTMLURL,
+		Description: bodyExcerpt(issue.Body),
+		Color:       issueColor(issue),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s in %s/%s opened by %s", kind, ref.Owner, ref.Repo, issue.Author),
+		},
+		Timestamp: issue.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "State", Value: issueStateText(issue), Inline: true},
+			{Name: "Comments", Value: strconv.Itoa(issue.Comments), Inline: true},
+		},
+	}
+
+	if issue.Milestone != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Milestone", Value: issue.Milestone, Inline: true})
+	}
+	if len(issue.Labels) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Labels",
+			Value: truncateText("`"+strings.Join(issue.Labels, "` `")+"`", maxEmbedFieldValue),
+		})
+	}
+	if len(issue.Assignees) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Assignees",
+			Value: truncateText(strings.Join(issue.Assignees, ", "), maxEmbedFieldValue),
+		})
+	}
+	if len(pulls) > 0 {
+		lines := make([]string, 0, maxLinkedPullRequests)
+		for index, pull := range pulls {
+			if index == maxLinkedPullRequests {
+				lines = append(lines, fmt.Sprintf("and %d more", len(pulls)-maxLinkedPullRequests))
+				break
+			}
+			lines = append(lines, fmt.Sprintf("[#%d %s](%s) (%s)", pull.Number, truncateText(pull.Title, 80), pull.HTMLURL, pull.State))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Linked pull requests",
+			Value: truncateText(strings.Join(lines, "\n"), maxEmbedFieldValue),
+		})
+	}
+	return embed
+}
+
+func issueStateText(issue *github.IssueDetails) string {
+	if issue.State != "closed" {
+		return "🟢 Open"
+	}
+	if issue.StateReason == "not_planned" {
+		return "⚪ Closed (not planned)"
+	}
+	return "🟣 Closed"
+}
+
+func issueColor(issue *github.IssueDetails) int {
+	if issue.State != "closed" {
+		return colorOpen
+	}
+	if issue.StateReason == "not_planned" {
+		return colorNotPlanned
+	}
+	return colorCompleted
+}
+
+// bodyExcerpt returns the first non-empty lines of an issue body, skipping
+// HTML comments left over from templates
+func bodyExcerpt(body string) string {
+	lines := make([]string, 0, issueExcerptLines)
+	inComment := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "<!--") {
+			inComment = !strings.Contains(trimmed, "-->")
+			continue
+		}
+		if inComment {
+			inComment = !strings.Contains(trimmed, "-->")
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		lines = append(lines, trimmed)
+		if len(lines) == issueExcerptLines {
+			break
+		}
+	}
+	return truncateText(strings.Join(lines, "\n"), issueExcerptLength)
+}
+
+// handleIssueAutocomplete suggests issue references in the configured
+// repositories, with the channel's repository first
+func handleIssueAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	typed := ""
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Focused {
+			typed = option.StringValue()
+		}
+	}
+
+	repositories := config.GetRepositories()
+	if repository, ok := config.GetChannelRepository(i.ChannelID); ok {
+		repositories = append([]config.Repository{repository}, repositories...)
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: issueChoices(typed, repositories),
+		},
+	})
+}
+
+// issueChoices builds "owner/repo#number" suggestions for what the user has
+// typed so far: a number is offered in every repository, text narrows the
+// repositories down. Until a number is typed only the repositories are
+// offered, named so it's clear a number is still needed.
+func issueChoices(typed string, repositories []config.Repository) []*discordgo.ApplicationCommandOptionChoice {
+	typed = strings.TrimSpace(typed)
+	repoPart, number, hasNumber := strings.Cut(typed, "#")
+	if !hasNumber {
+		if _, err := strconv.Atoi(typed); err == nil {
+			repoPart, number = "", typed
+		}
+	}
+	repoPart = strings.ToLower(repoPart)
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 25)
+	seen := make(map[string]bool)
+	for _, repository := range repositories {
+		if repoPart != "" && !strings.Contains(strings.ToLower(repository.String()), repoPart) {
+			continue
+		}
+
+		value, name := repository.String(), repository.String()+"#… (type the issue number)"
+		if number != "" {
+			value += "#" + number
+			name = value
+		}
+		if seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: value})
+
+		// Discord limits autocomplete to 25 choices
+		if len(choices) >= 25 {
+			break
+		}
+	}
+	return choices
+}
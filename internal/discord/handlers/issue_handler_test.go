@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+func TestBuildIssueEmbed(t *testing.T) {
+	ref := github.IssueRef{Owner: "meshtastic", Repo: "firmware", Number: 7}
+	issue := &github.IssueDetails{
+		Number:      7,
+		Title:       "Crash on boot",
+		Body:        "<!-- Describe the bug -->\n\nThe node crashes\n\nwhen booting",
+		State:       "closed",
+		StateReason: "not_planned",
+		HTMLURL:     "https://github.com/meshtastic/firmware/issues/7",
+		Author:      "alice",
+		Labels:      []string{"bug", "from-discord"},
+		Comments:    2,
+	}
+	pulls := []*github.IssueSummary{{Number: 9, Title: "Fix crash", State: "closed", HTMLURL: "https://github.com/meshtastic/firmware/pull/9"}}
+
+	embed := buildIssueEmbed(ref, issue, pulls)
+
+	if embed.Title != "#7 Crash on boot" || embed.URL != issue.HTMLURL {
+		t.Errorf("title = %q, url = %q", embed.Title, embed.URL)
+	}
+	if embed.Description != "The node crashes\nwhen booting" {
+		t.Errorf("description = %q", embed.Description)
+	}
+	if embed.Color != colorNotPlanned {
+		t.Errorf("color = %x, want %x", embed.Color, colorNotPlanned)
+	}
+	if embed.Footer.Text != "Issue in meshtastic/firmware opened by alice" {
+		t.Errorf("footer = %q", embed.Footer.Text)
+	}
+
+	fields := make(map[string]string)
+	for _, field := range embed.Fields {
+		fields[field.Name] = field.Value
+	}
+	expected := map[string]string{
+		"State":                "⚪ Closed (not planned)",
+		"Comments":             "2",
+		"Labels":               "`bug` `from-discord`",
+		"Linked pull requests": "[#9 Fix crash](https://github.com/meshtastic/firmware/pull/9) (closed)",
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Errorf("field %q = %q, want %q", name, fields[name], value)
+		}
+	}
+	if _, ok := fields["Assignees"]; ok {
+		t.Error("unexpected Assignees field for an unassigned issue")
+	}
+}
+
+func TestBodyExcerpt_Limits(t *testing.T) {
+	body := strings.Repeat("line\n", 20)
+	if got := strings.Count(bodyExcerpt(body), "\n") + 1; got != issueExcerptLines {
+		t.Errorf("excerpt has %d lines, want %d", got, issueExcerptLines)
+	}
+
+	long := strings.Repeat("a", issueExcerptLength*2)
+	if got := len([]rune(bodyExcerpt(long))); got != issueExcerptLength {
+		t.Errorf("excerpt is %d characters, want %d", got, issueExcerptLength)
+	}
+}
+
+func TestIssueChoices(t *testing.T) {
+	repositories := []config.Repository{
+		{Owner: "meshtastic", Repo: "web"},
+		{Owner: "meshtastic", Repo: "firmware"},
+		{Owner: "meshtastic", Repo: "web"},
+	}
+
+	tests := []struct {
+		name     string
+		typed    string
+		expected []string
+	}{
+		{name: "number", typed: "12", expected: []string{"meshtastic/web#12", "meshtastic/firmware#12"}},
+		{name: "repository and number", typed: "firm#5", expected: []string{"meshtastic/firmware#5"}},
+		{name: "repository", typed: "web", expected: []string{"meshtastic/web"}},
+		{name: "nothing typed", typed: "", expected: []string{"meshtastic/web", "meshtastic/firmware"}},
+		{name: "no match", typed: "android#1", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := issueChoices(tt.typed, repositories)
+			values := make([]string, 0, len(choices))
+			for _, choice := range choices {
+				values = append(values, choice.Value.(string))
+			}
+			if strings.Join(values, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("issueChoices(%q) = %v, want %v", tt.typed, values, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKnownRepository(t *testing.T) {
+	GithubOwner, GithubRepo = "meshtastic", "web"
+	defer func() { GithubOwner, GithubRepo = "", "" }()
+
+	tests := []struct {
+		ref      github.IssueRef
+		expected bool
+	}{
+		{ref: github.IssueRef{Owner: "meshtastic", Repo: "web", Number: 1}, expected: true},
+		{ref: github.IssueRef{Owner: "Meshtastic", Repo: "Web", Number: 1}, expected: true},
+		{ref: github.IssueRef{Owner: "meshtastic", Repo: "private-infra", Number: 1}, expected: false},
+		{ref: github.IssueRef{Owner: "someone", Repo: "web", Number: 1}, expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := knownRepository(tt.ref); got != tt.expected {
+			t.Errorf("knownRepository(%s) = %v, want %v", tt.ref, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// interactionLifetime is how long Discord accepts responses to an
+	// interaction, so GitHub calls for it are given up after that
+	interactionLifetime = 15 * time.Minute
+	// backgroundTimeout bounds work that isn't tied to an interaction, like
+	// unfurling or retrying queued reports
+	backgroundTimeout = 2 * time.Minute
+	// cancelGrace is how long Drain waits for work to stop after cancelling it
+	cancelGrace = 5 * time.Second
+)
+
+// rootCtx is the parent of every GitHub call, cancelled when shutdown runs
+// out of time
+var rootCtx, cancelRoot = context.WithCancel(context.Background())
+
+// InitializeContext ties GitHub calls to ctx, so they're cancelled with it
+func InitializeContext(ctx context.Context) {
+	rootCtx, cancelRoot = context.WithCancel(ctx)
+}
+
+// interactionContext returns the context for GitHub calls made handling an
+// interaction, which ends when Discord stops accepting responses to it
+func interactionContext(i *discordgo.InteractionCreate) (context.Context, context.CancelFunc) {
+	created, err := discordgo.SnowflakeTimestamp(i.ID)
+	if err != nil {
+		created = time.Now()
+	}
+	return context.WithDeadline(rootCtx, created.Add(interactionLifetime))
+}
+
+// backgroundContext returns the context for GitHub calls that aren't made
+// for an interaction
+func backgroundContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(rootCtx, backgroundTimeout)
+}
+
+// workTracker counts interactions and deliveries in progress, so shutdown
+// can wait for them
+type workTracker struct {
+	mu       sync.Mutex
+	draining bool
+	wg       sync.WaitGroup
+}
+
+var work = &workTracker{}
+
+// start records that work began, or returns false once shutdown started
+func (w *workTracker) start() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.draining {
+		return false
+	}
+	w.wg.Add(1)
+	return true
+}
+
+func (w *workTracker) done() {
+	w.wg.Done()
+}
+
+// drain stops new work from starting and returns a channel that is closed
+// once the work in progress is done
+func (w *workTracker) drain() <-chan struct{} {
+	w.mu.Lock()
+	w.draining = true
+	w.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(finished)
+	}()
+	return finished
+}
+
+// TrackRequests wraps an HTTP handler so Drain waits for the requests it's
+// handling. Requests arriving once shutdown started are turned away.
+func TrackRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !work.start() {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		defer work.done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Drain stops handling new interactions and waits for the ones in progress,
+// like issues being created, to finish. GitHub calls still running when ctx
+// is done are cancelled; reports cut short stay queued in the outbox.
+func Drain(ctx context.Context) error {
+	finished := work.drain()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+	}
+
+	cancelRoot()
+	select {
+	case <-finished:
+	case <-time.After(cancelGrace):
+	}
+	return fmt.Errorf("in-flight work was cancelled: %w", ctx.Err())
+}
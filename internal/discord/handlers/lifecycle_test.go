@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useTestLifecycle gives the test its own root context and work tracker
+func useTestLifecycle(t *testing.T) {
+	t.Helper()
+
+	previousWork, previousCtx, previousCancel := work, rootCtx, cancelRoot
+	work = &workTracker{}
+	InitializeContext(context.Background())
+	t.Cleanup(func() {
+		cancelRoot()
+		work, rootCtx, cancelRoot = previousWork, previousCtx, previousCancel
+	})
+}
+
+func TestDrain_WaitsForWork(t *testing.T) {
+	useTestLifecycle(t)
+
+	if !work.start() {
+		t.Fatal("start() before Drain() returned false")
+	}
+	finished := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(finished)
+		work.done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := Drain(ctx); err != nil {
+		t.Fatalf("Drain() unexpected error: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Drain() returned before the work in progress finished")
+	}
+	if rootCtx.Err() != nil {
+		t.Error("Drain() cancelled GitHub calls although the work finished in time")
+	}
+	if work.start() {
+		t.Error("start() after Drain() returned true")
+	}
+}
+
+func TestDrain_CancelsWorkThatRunsOver(t *testing.T) {
+	useTestLifecycle(t)
+
+	work.start()
+	go func() {
+		ctx, cancel := backgroundContext()
+		defer cancel()
+		<-ctx.Done()
+		work.done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Drain(ctx); err == nil {
+		t.Error("Drain() expected an error when work had to be cancelled")
+	}
+	if rootCtx.Err() == nil {
+		t.Error("Drain() didn't cancel GitHub calls that ran over")
+	}
+}
+
+func TestTrackRequests(t *testing.T) {
+	useTestLifecycle(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := TrackRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	served := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/github/webhook", nil))
+		served <- recorder.Code
+	}()
+	<-started
+
+	drained := make(chan error)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		drained <- Drain(ctx)
+	}()
+	select {
+	case <-drained:
+		t.Fatal("Drain() returned while a request was being handled")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if code := <-served; code != http.StatusAccepted {
+		t.Errorf("tracked request status = %d, want %d", code, http.StatusAccepted)
+	}
+	if err := <-drained; err != nil {
+		t.Errorf("Drain() unexpected error: %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/github/webhook", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("status after Drain() = %d, want %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
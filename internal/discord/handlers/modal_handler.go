@@ -1,54 +1,265 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/meshtastic/meshtastic-bot/internal/config"
 	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func createIssueFromState(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey string, includeMarkdownNote bool) {
-	body := buildIssueBody(state.SubmittedValues, i.Member.User.Username, i.Member.User.ID)
-	issue, err := GithubClient.CreateIssue(state.Owner, state.Repo, state.Title, body, state.Labels)
+// openIssueModal starts an issue report for a command in the current channel,
+// titled with the command's title option
+func openIssueModal(s *discordgo.Session, i *discordgo.InteractionCreate, command string, notConfiguredMessage string) {
+	state, err := newReportState(i, command, commandOptionString(i, "title"))
 	if err != nil {
-		log.Printf("Failed to create GitHub issue: %v", err)
+		log.Printf("Error getting modal fields: %v", err)
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "❌ Failed to create issue. Please try again later.",
+				Content: notConfiguredMessage,
 				Flags:   discordgo.MessageFlagsEphemeral,
 			},
 		})
-		delete(modalStates, stateKey)
 		return
 	}
 
-	confirmationMessage := fmt.Sprintf("✅ Issue #%d created successfully!\n%s", issue.Number, issue.HTMLURL)
-	if includeMarkdownNote {
-		confirmationMessage += "\n\n**Note:** You can use Markdown formatting in your descriptions. " +
-			"To add images or other attachments, please edit the issue directly on GitHub."
+	startReport(s, i, state)
+}
+
+// newReportState builds the state of a new report for a command in the
+// current channel. The title gets the template's prefix, or is the template
+// name when empty. Labels, assignees and the milestone come from the modal's
+// config and template.
+func newReportState(i *discordgo.InteractionCreate, command string, title string) (*ModalState, error) {
+	definition, err := config.GetModalDefinition(command, i.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	title = github.FormatIssueTitle(definition.TitlePrefix, title)
+	if title == "" {
+		title = definition.Name
+	}
+
+	return &ModalState{
+		Title:            title,
+		AllFields:        definition.Fields,
+		SubmittedValues:  make(map[string]string),
+		Labels:           definition.Labels,
+		Assignees:        definition.Assignees,
+		Milestone:        definition.Milestone,
+		Project:          definition.Project,
+		Command:          command,
+		ChannelID:        i.ChannelID,
+		UserID:           i.Member.User.ID,
+		Owner:            definition.Owner,
+		Repo:             definition.Repo,
+		Selections:       make(map[string][]string),
+		AttachmentLimits: definition.Attachments,
+		OpenThread:       definition.Thread,
+		InteractionID:    i.ID,
+		Tracker:          definition.Tracker,
+	}, nil
+}
+
+// startReport stores the state of a new report so the user's title is used on
+// submission, then shows the first part of the report
+func startReport(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState) {
+	stateKey := fmt.Sprintf("%s_%s_%s", state.Command, i.ChannelID, i.Member.User.ID)
+	sessions.Set(stateKey, state)
+
+	steps := planSteps(state.AllFields)
+	if len(steps) > 0 && steps[0].Select {
+		showSelectStep(s, i, state, stateKey, "")
+		return
+	}
+
+	endIndex := 0
+	if len(steps) > 0 {
+		endIndex = steps[0].End
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: buildModal(fmt.Sprintf("modal_%s_%s", state.Command, i.ChannelID), state.Title,
+			state.AllFields[:endIndex], state.SubmittedValues),
+	})
+	if err != nil {
+		log.Printf("Error responding with modal: %v", err)
+	}
+}
+
+// advanceReport shows the next step of a report, or the review screen once
+// every step has been completed. Once the user has reached the review screen,
+// completing an edited step returns there.
+func advanceReport(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey string) {
+	steps := planSteps(state.AllFields)
+	if state.Reviewing || state.Step >= len(steps) {
+		reviewed, exists := sessions.Update(stateKey, func(state *ModalState) {
+			state.Reviewing = true
+			state.Step = len(steps)
+		})
+		if !exists {
+			respondSessionExpired(s, i, stateKey)
+			return
+		}
+		showReview(s, i, reviewed, stateKey)
+		return
+	}
+	showStep(s, i, state, stateKey)
+}
+
+// showStep shows the step at state.Step, prefilled with any earlier answers
+func showStep(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey string) {
+	steps := planSteps(state.AllFields)
+	step := steps[state.Step]
+	if step.Select {
+		showSelectStep(s, i, state, stateKey, "")
+		return
 	}
 
+	// A modal can't be opened in response to another modal, so ask the
+	// user to click Continue first. Back goes to the step before the one
+	// just completed, which the user can reach again with Continue.
+	if i.Type == discordgo.InteractionModalSubmit {
+		message := fmt.Sprintf("Part %d of %d complete. Click 'Continue' to proceed.",
+			state.Step, len(steps))
+
+		err := respondEphemeralMessage(s, i, &discordgo.InteractionResponseData{
+			Content: message,
+			Components: []discordgo.MessageComponent{
+				navigationRow(stateKey, discordgo.Button{
+					Label:    "Continue",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("continue_%s", stateKey),
+				}, state.Step-2),
+			},
+		})
+		if err != nil {
+			log.Printf("Error responding with continue button: %v", err)
+		}
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: buildModal(fmt.Sprintf("modal_continue_%s", stateKey), state.Title,
+			state.AllFields[step.Start:step.End], state.SubmittedValues),
+	})
+	if err != nil {
+		log.Printf("Error showing next modal: %v", err)
+	}
+}
+
+// navigationRow returns a row with the button that moves the report forward,
+// a Back button to backStep unless it's negative and a Cancel button
+func navigationRow(stateKey string, forward discordgo.Button, backStep int) discordgo.ActionsRow {
+	buttons := []discordgo.MessageComponent{forward}
+	if backStep >= 0 {
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Back",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("back_%d_%s", backStep, stateKey),
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Cancel",
+		Style:    discordgo.DangerButton,
+		CustomID: fmt.Sprintf("cancel_%s", stateKey),
+	})
+	return discordgo.ActionsRow{Components: buttons}
+}
+
+// storeModalValues records the text inputs submitted in a modal, keyed by field CustomID
+func storeModalValues(state *ModalState, components []discordgo.MessageComponent) {
+	for customID, value := range extractModalFields(components) {
+		state.SubmittedValues[customID] = value
+	}
+}
+
+// respondSessionExpired tells the user their modal state is gone
+func respondSessionExpired(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	log.Printf("Modal state not found for key: %s", stateKey)
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: confirmationMessage,
+			Content: "❌ Session expired. Please start over.",
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
 	})
+}
 
-	delete(modalStates, stateKey)
+// createIssueFromState submits a completed report to be filed on GitHub. The
+// interaction must already be deferred because the GitHub API can take longer
+// than Discord's interaction deadline.
+func createIssueFromState(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey string) {
+	sessions.Delete(stateKey)
+	submitReport(s, i, state, state.issueBody(i.Member.User.Username, i.Member.User.ID))
+}
+
+// finishReport adds a newly filed issue to its project board, attaches the
+// report's files, opens its thread and records it, returning the
+// confirmation for the reporter
+func finishReport(ctx context.Context, s *discordgo.Session, guildID string, state *ModalState, body string, issue *github.IssueResponse) string {
+	confirmationMessage := fmt.Sprintf("✅ Issue #%d created successfully!\n%s", issue.Number, issue.HTMLURL)
+	confirmationMessage += addToProject(ctx, state.Project, issue)
+	if len(state.Attachments) > 0 {
+		comment := attachmentsMarkdown(state.Attachments)
+		if _, err := issueTracker(state.Tracker).CreateComment(ctx, state.Owner, state.Repo, issue.Number, comment); err != nil {
+			log.Printf("Failed to add attachments to issue #%d: %v", issue.Number, err)
+			confirmationMessage += fmt.Sprintf("\n\n⚠️ Your attachments could not be added, please add them to the issue on %s.", state.Tracker.Name())
+		} else if hasLinkedAttachments(state.Attachments) {
+			confirmationMessage += fmt.Sprintf("\n\n📎 Images and other files are linked from Discord, where links expire after about a day. "+
+				"Please add any that matter to the issue on %s.", state.Tracker.Name())
+		}
+	} else if state.AttachmentLimits == nil && len(planSteps(state.AllFields)) > 1 {
+		confirmationMessage += "\n\n**Note:** You can use Markdown formatting in your descriptions. " +
+			fmt.Sprintf("To add images or other attachments, please edit the issue directly on %s.", state.Tracker.Name())
+	}
+
+	record := store.IssueRecord{
+		Owner:     state.Owner,
+		Repo:      state.Repo,
+		Number:    issue.Number,
+		Title:     state.Title,
+		HTMLURL:   issue.HTMLURL,
+		UserID:    state.UserID,
+		ChannelID: state.ChannelID,
+		CreatedAt: time.Now(),
+	}
+	if !state.Tracker.IsGitHub() {
+		record.Tracker, record.TrackerURL = state.Tracker.Kind, state.Tracker.URL
+	}
+
+	if state.OpenThread {
+		threadID, err := openIssueThread(ctx, s, guildID, state, issue, body)
+		if err != nil {
+			log.Printf("Failed to set up thread for issue #%d: %v", issue.Number, err)
+		}
+		if threadID != "" {
+			record.ThreadID = threadID
+			confirmationMessage += fmt.Sprintf("\n\n💬 Follow-up discussion: <#%s>", threadID)
+		}
+	}
+	issues.Record(record)
+	drafts.Delete(state.UserID, draftID(state))
+
+	return confirmationMessage
 }
 
 func handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	data := i.ModalSubmitData()
 
 	// Determine which command this modal is for based on CustomID
-	// Format: "modal_<command>_<channelID>" or "modal_continue_<stateKey>"
+	// Format: "modal_<command>_<channelID>", "modal_continue_<stateKey>" or
+	// "modal_title_<stateKey>"
 	parts := strings.Split(data.CustomID, "_")
 	if len(parts) < 2 {
 		log.Printf("Invalid modal CustomID format: %s", data.CustomID)
@@ -60,274 +271,146 @@ func handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		handleModalContinuation(s, i, strings.Join(parts[2:], "_"))
 		return
 	}
+	if parts[1] == "title" && len(parts) >= 3 {
+		handleTitleSubmit(s, i, strings.Join(parts[2:], "_"))
+		return
+	}
 
 	command := parts[1]
 	channelID := i.ChannelID
 
-	// Check if this is a multi-part modal
+	// Check for the modal state stored when the command was invoked
 	stateKey := fmt.Sprintf("%s_%s_%s", command, channelID, i.Member.User.ID)
-	state, isMultiPart := modalStates[stateKey]
-
-	if isMultiPart {
-		// This is the first part of a multi-part modal
-		// Extract and store the submitted values
-		for _, component := range data.Components {
-			if actionRow, ok := component.(*discordgo.ActionsRow); ok {
-				for _, comp := range actionRow.Components {
-					if textInput, ok := comp.(*discordgo.TextInput); ok {
-						// Find the field label from the original fields
-						fieldLabel := textInput.CustomID
-						for _, field := range state.AllFields {
-							if field.CustomID == textInput.CustomID {
-								fieldLabel = field.Label
-								break
-							}
-						}
-						state.SubmittedValues[fieldLabel] = textInput.Value
-					}
-				}
-			}
-		}
-
-		currentIndex := len(state.SubmittedValues)
-
-		// Check if there are more fields to show
-		if currentIndex < len(state.AllFields) {
-			totalParts := (len(state.AllFields) + 4) / 5
-			currentPart := (currentIndex + 4) / 5
-			message := fmt.Sprintf("Part %d of %d complete. Click 'Continue' to proceed.",
-				currentPart, totalParts)
-
-			err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: message,
-					Flags:   discordgo.MessageFlagsEphemeral,
-					Components: []discordgo.MessageComponent{
-						discordgo.ActionsRow{
-							Components: []discordgo.MessageComponent{
-								discordgo.Button{
-									Label:    "Continue",
-									Style:    discordgo.PrimaryButton,
-									CustomID: fmt.Sprintf("continue_%s", stateKey),
-								},
-							},
-						},
-					},
-				},
-			})
-			if err != nil {
-				log.Printf("Error responding with continue button: %v", err)
-			}
-			return
-		}
-
-		// All fields collected - create the GitHub issue
-		createIssueFromState(s, i, state, stateKey, false)
-		return
-	}
+	state, hasState := updateProgress(stateKey, func(state *ModalState) {
+		storeModalValues(state, data.Components)
+		state.Step++
+	})
 
-	// Simple modal (5 or fewer fields)
-	// Get owner and repo from modal config
-	_, _, owner, repo, err := config.GetAllFieldsForModal(command, channelID)
-	if err != nil {
-		log.Printf("Error getting modal config: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "❌ Failed to create issue. Configuration error.",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
+	if hasState {
+		// This was the first part of the modal
+		advanceReport(s, i, state, stateKey)
 		return
 	}
 
-	// Extract field values
+	// No stored state (e.g. the bot restarted while the modal was open),
+	// fall back to the legacy title and description fields
 	fields := extractModalFields(data.Components)
 
-	// Get user info
-	username := i.Member.User.Username
-	userID := i.Member.User.ID
-
-	// Determine labels based on command
-	labels := []string{"from-discord"}
-	switch command {
-	case "bug":
-		labels = append(labels, "bug")
-	case "feature":
-		labels = append(labels, "enhancement")
-	}
-
-	// Create GitHub issue
 	title := fields["bug_title"]
 	if title == "" {
 		title = fields["feature_title"]
 	}
+	if title == "" {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
 
 	description := fields["bug_description"]
 	if description == "" {
 		description = fields["feature_description"]
 	}
 
-	body := github.FormatIssueBody(username, userID, description)
+	if err := deferEphemeralResponse(s, i); err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
 
-	issue, err := GithubClient.CreateIssue(owner, repo, title, body, labels)
+	// Get owner, repo and labels from modal config
+	definition, err := config.GetModalDefinition(command, channelID)
 	if err != nil {
-		log.Printf("Failed to create GitHub issue: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "❌ Failed to create issue. Please try again later.",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
+		log.Printf("Error getting modal config: %v", err)
+		editDeferredResponse(s, i, "❌ Failed to create issue. Configuration error.")
 		return
 	}
 
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("✅ Issue #%d created successfully!\n%s", issue.Number, issue.HTMLURL),
-			Flags:   discordgo.MessageFlagsEphemeral,
-		},
-	})
-}
+	// Get user info
+	username := i.Member.User.Username
+	userID := i.Member.User.ID
 
-// handleModalContinuation processes multi-part modal submissions
-func handleModalContinuation(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
-	state, exists := modalStates[stateKey]
-	if !exists {
-		log.Printf("Modal state not found for key: %s", stateKey)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "❌ Session expired. Please start over.",
-				Flags:   discordgo.MessageFlagsEphemeral,
-			},
-		})
-		return
+	report := &ModalState{
+		Title:     title,
+		Labels:    definition.Labels,
+		Assignees: definition.Assignees,
+		Milestone: definition.Milestone,
+		Project:   definition.Project,
+		Command:   command,
+		ChannelID: channelID,
+		UserID:    userID,
+		Owner:     definition.Owner,
+		Repo:      definition.Repo,
+		Tracker:   definition.Tracker,
 	}
+	submitReport(s, i, report, github.FormatIssueBody(username, userID, description))
+}
 
-	// Extract submitted values
-	data := i.ModalSubmitData()
-	for _, component := range data.Components {
-		if actionRow, ok := component.(*discordgo.ActionsRow); ok {
-			for _, comp := range actionRow.Components {
-				if textInput, ok := comp.(*discordgo.TextInput); ok {
-					customID := textInput.CustomID
-					value := textInput.Value
-
-					// Find the field label from the original fields
-					fieldLabel := customID // default to customID
-					for _, field := range state.AllFields {
-						if field.CustomID == customID {
-							fieldLabel = field.Label
-							break
-						}
-					}
-
-					state.SubmittedValues[fieldLabel] = value
-				}
-			}
-		}
+// addToProject adds a new issue to the modal's project board, if it has
+// one. The issue is kept when this fails; the returned warning is appended to
+// the confirmation so someone can add it by hand.
+func addToProject(ctx context.Context, project *config.ProjectConfig, issue *github.IssueResponse) string {
+	if project == nil {
+		return ""
 	}
 
-	currentIndex := len(state.SubmittedValues)
-
-	// Check if there are more fields to show
-	if currentIndex < len(state.AllFields) {
-		totalParts := (len(state.AllFields) + 4) / 5
-		currentPart := (currentIndex + 4) / 5
-		message := fmt.Sprintf("Part %d of %d complete. Click 'Continue' to proceed.",
-			currentPart, totalParts)
+	err := GithubClient.AddIssueToProject(ctx, github.Project{Owner: project.Owner, Number: project.Number}, issue.NodeID, project.Fields)
+	if err != nil {
+		log.Printf("Failed to add issue #%d to project %s/%d: %v", issue.Number, project.Owner, project.Number, err)
+		return "\n\n⚠️ The issue couldn't be fully added to the project board, a maintainer will need to triage it there."
+	}
+	return ""
+}
 
-		// Create continue button
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: message,
-				Flags:   discordgo.MessageFlagsEphemeral,
-				Components: []discordgo.MessageComponent{
-					discordgo.ActionsRow{
-						Components: []discordgo.MessageComponent{
-							discordgo.Button{
-								Label:    "Continue",
-								Style:    discordgo.PrimaryButton,
-								CustomID: fmt.Sprintf("continue_%s", stateKey),
-							},
-						},
-					},
-				},
-			},
-		})
-		if err != nil {
-			log.Printf("Error responding with continue button: %v", err)
-		}
+// handleModalContinuation processes multi-part modal submissions
+func handleModalContinuation(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	state, exists := updateProgress(stateKey, func(state *ModalState) {
+		storeModalValues(state, i.ModalSubmitData().Components)
+		state.Step++
+	})
+	if !exists {
+		respondSessionExpired(s, i, stateKey)
 		return
 	}
-
-	// All fields collected - create the GitHub issue
-	createIssueFromState(s, i, state, stateKey, true)
+	advanceReport(s, i, state, stateKey)
 }
 
+// handleButtonClick handles message component interactions (buttons and select menus)
 func handleButtonClick(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	customID := i.MessageComponentData().CustomID
 
-	// Check if this is a continue button
-	if strings.HasPrefix(customID, "continue_") {
+	switch {
+	case strings.HasPrefix(customID, "continue_"):
+		// Show the next modal chunk
 		stateKey := strings.TrimPrefix(customID, "continue_")
-		state, exists := modalStates[stateKey]
+		state, exists := sessions.Get(stateKey)
 		if !exists {
-			log.Printf("Modal state not found for key: %s", stateKey)
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "❌ Session expired. Please start over.",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			})
+			respondSessionExpired(s, i, stateKey)
 			return
 		}
-
-		// Show the next modal chunk
-		currentIndex := len(state.SubmittedValues)
-		endIndex := currentIndex + 5
-		if endIndex > len(state.AllFields) {
-			endIndex = len(state.AllFields)
-		}
-		nextChunk := state.AllFields[currentIndex:endIndex]
-
-		// Build modal components
-		components := make([]discordgo.MessageComponent, 0, len(nextChunk))
-		for _, field := range nextChunk {
-			style := discordgo.TextInputShort
-			if field.Style == "paragraph" {
-				style = discordgo.TextInputParagraph
-			}
-
-			components = append(components, discordgo.ActionsRow{
-				Components: []discordgo.MessageComponent{
-					discordgo.TextInput{
-						CustomID:    field.CustomID,
-						Label:       field.Label,
-						Style:       style,
-						Placeholder: truncatePlaceholder(field.Placeholder),
-						Required:    field.Required,
-					},
-				},
-			})
-		}
-
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseModal,
-			Data: &discordgo.InteractionResponseData{
-				CustomID:   fmt.Sprintf("modal_continue_%s", stateKey),
-				Title:      state.Title,
-				Components: components,
-			},
-		})
-		if err != nil {
-			log.Printf("Error showing next modal: %v", err)
-		}
+		advanceReport(s, i, state, stateKey)
+	case strings.HasPrefix(customID, "select_"):
+		handleSelectMenu(s, i)
+	case strings.HasPrefix(customID, "selectdone_"):
+		handleSelectDone(s, i)
+	case strings.HasPrefix(customID, "back_"):
+		handleBack(s, i)
+	case strings.HasPrefix(customID, "edit_"):
+		handleEdit(s, i)
+	case strings.HasPrefix(customID, "edittitle_"):
+		handleEditTitle(s, i, strings.TrimPrefix(customID, "edittitle_"))
+	case strings.HasPrefix(customID, "submit_"):
+		handleSubmit(s, i, strings.TrimPrefix(customID, "submit_"))
+	case strings.HasPrefix(customID, "cancel_"):
+		handleCancel(s, i, strings.TrimPrefix(customID, "cancel_"))
+	case strings.HasPrefix(customID, "clearattach_"):
+		handleClearAttachments(s, i, strings.TrimPrefix(customID, "clearattach_"))
+	case strings.HasPrefix(customID, "dupe_"):
+		handleDuplicate(s, i)
+	case strings.HasPrefix(customID, "filenew_"):
+		handleFileNew(s, i, strings.TrimPrefix(customID, "filenew_"))
+	case strings.HasPrefix(customID, "draftresume_"):
+		handleDraftResume(s, i, strings.TrimPrefix(customID, "draftresume_"))
+	case strings.HasPrefix(customID, "draftdiscard_"):
+		handleDraftDiscard(s, i, strings.TrimPrefix(customID, "draftdiscard_"))
+	case strings.HasPrefix(customID, "myissues_"):
+		handleMyIssuesPage(s, i, strings.TrimPrefix(customID, "myissues_"))
 	}
 }
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// myIssuesPageSize is the number of issues on a /myissues page. Each one is
+// looked up on GitHub for its current state.
+const myIssuesPageSize = 5
+
+// handleMyIssues lists the issues the user filed through the bot
+func handleMyIssues(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+	showMyIssues(s, i, 0)
+}
+
+// handleMyIssuesPage shows another page of /myissues, from the buttons
+// with custom ID "myissues_<page>"
+func handleMyIssuesPage(s *discordgo.Session, i *discordgo.InteractionCreate, pageID string) {
+	page, err := strconv.Atoi(pageID)
+	if err != nil {
+		log.Printf("Invalid /myissues page %q", pageID)
+		return
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+	showMyIssues(s, i, page)
+}
+
+// showMyIssues edits the deferred response with a page of the user's
+// issues, their live state and Previous and Next buttons
+func showMyIssues(s *discordgo.Session, i *discordgo.InteractionCreate, page int) {
+	records := issues.ListByUser(i.Member.User.ID)
+	if len(records) == 0 {
+		editDeferredResponse(s, i, "You haven't filed any issues through the bot yet. Use /bug or /feature to report one.")
+		return
+	}
+
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+	pageRecords, page, pages := myIssuesPage(records, page)
+	lines := make([]string, 0, len(pageRecords))
+	for _, record := range pageRecords {
+		recordTracker := config.Tracker{Kind: record.Tracker, URL: record.TrackerURL}
+		issue, err := issueTracker(recordTracker).GetIssue(ctx, record.Owner, record.Repo, record.Number)
+		if err != nil {
+			log.Printf("Failed to get %s issue %s: %v", recordTracker.Name(), record.Key(), err)
+		}
+		lines = append(lines, describeMyIssue(record, issue))
+	}
+
+	content := ""
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "Your issues",
+		Description: truncateText(strings.Join(lines, "\n\n"), 4096),
+		Color:       colorOpen,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Page %d of %d • %d issues", page+1, pages, len(records)),
+		},
+	}}
+	components := []discordgo.MessageComponent{}
+	if pages > 1 {
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ Previous",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("myissues_%d", page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "Next ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("myissues_%d", page+1),
+					Disabled: page == pages-1,
+				},
+			},
+		})
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error editing deferred response: %v", err)
+	}
+}
+
+// myIssuesPage returns the records on a page, with the page clamped to the
+// ones that exist and the number of pages
+func myIssuesPage(records []store.IssueRecord, page int) ([]store.IssueRecord, int, int) {
+	pages := (len(records) + myIssuesPageSize - 1) / myIssuesPageSize
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	start := page * myIssuesPageSize
+	end := start + myIssuesPageSize
+	if end > len(records) {
+		end = len(records)
+	}
+	return records[start:end], page, pages
+}
+
+// describeMyIssue shows an issue's current state and labels, or only what
+// was recorded when it was filed if GitHub couldn't be reached
+func describeMyIssue(record store.IssueRecord, issue *github.IssueDetails) string {
+	title := record.Title
+	state := "❔ State unavailable"
+	labels := ""
+	if issue != nil {
+		title = issue.Title
+		state = issueStateText(issue)
+		if len(issue.Labels) > 0 {
+			labels = " · `" + strings.Join(issue.Labels, "` `") + "`"
+		}
+	}
+
+	return fmt.Sprintf("[%s/%s#%d %s](%s)\n%s%s · filed <t:%d:R>",
+		record.Owner, record.Repo, record.Number, truncateText(title, 80), record.HTMLURL,
+		state, labels, record.CreatedAt.Unix())
+}
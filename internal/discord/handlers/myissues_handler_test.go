@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+func TestMyIssuesPage(t *testing.T) {
+	records := make([]store.IssueRecord, 12)
+	for index := range records {
+		records[index].Number = index + 1
+	}
+
+	tests := []struct {
+		name          string
+		page          int
+		expectedPage  int
+		expectedFirst int
+		expectedCount int
+	}{
+		{name: "first page", page: 0, expectedPage: 0, expectedFirst: 1, expectedCount: 5},
+		{name: "last page", page: 2, expectedPage: 2, expectedFirst: 11, expectedCount: 2},
+		{name: "past the end", page: 7, expectedPage: 2, expectedFirst: 11, expectedCount: 2},
+		{name: "negative", page: -1, expectedPage: 0, expectedFirst: 1, expectedCount: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageRecords, page, pages := myIssuesPage(records, tt.page)
+			if page != tt.expectedPage || pages != 3 {
+				t.Errorf("page = %d of %d, want %d of 3", page, pages, tt.expectedPage)
+			}
+			if len(pageRecords) != tt.expectedCount || pageRecords[0].Number != tt.expectedFirst {
+				t.Errorf("got %d records starting at #%d, want %d starting at #%d",
+					len(pageRecords), pageRecords[0].Number, tt.expectedCount, tt.expectedFirst)
+			}
+		})
+	}
+}
+
+func TestDescribeMyIssue(t *testing.T) {
+	record := store.IssueRecord{
+		Owner:     "meshtastic",
+		Repo:      "web",
+		Number:    12,
+		Title:     "Map is blank",
+		HTMLURL:   "https://github.com/meshtastic/web/issues/12",
+		CreatedAt: time.Unix(1700000000, 0),
+	}
+
+	tests := []struct {
+		name     string
+		issue    *github.IssueDetails
+		expected string
+	}{
+		{
+			name:     "live state",
+			issue:    &github.IssueDetails{Title: "Map is blank on Firefox", State: "closed", Labels: []string{"bug", "fixed"}},
+			expected: "[meshtastic/web#12 Map is blank on Firefox](https://github.com/meshtastic/web/issues/12)\n🟣 Closed · `bug` `fixed` · filed <t:1700000000:R>",
+		},
+		{
+			name:     "GitHub unavailable",
+			issue:    nil,
+			expected: "[meshtastic/web#12 Map is blank](https://github.com/meshtastic/web/issues/12)\n❔ State unavailable · filed <t:1700000000:R>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeMyIssue(record, tt.issue); got != tt.expected {
+				t.Errorf("describeMyIssue() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// outboxMaxAttempts is how often a report is tried before it's kept as
+	// a draft instead
+	outboxMaxAttempts = 10
+	// outboxMaxBackoff caps the growing delay between attempts
+	outboxMaxBackoff = 30 * time.Minute
+	// followUpWindow is how long after submitting a report the reporter is
+	// notified with a follow-up rather than a DM. Discord accepts follow-ups
+	// for 15 minutes.
+	followUpWindow = 14 * time.Minute
+	// markerClockSkew is how much earlier than a report was queued an issue
+	// filed for it may appear to have been created
+	markerClockSkew = time.Minute
+)
+
+// submitReport queues a completed report in the outbox and tries to file it
+// right away, editing the deferred response with the outcome. Reports
+// GitHub couldn't take yet are retried by StartOutbox.
+func submitReport(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, body string) {
+	entry := &OutboxEntry{
+		ID:       submissionID(state, body),
+		State:    state,
+		Body:     body,
+		AppID:    i.AppID,
+		Token:    i.Token,
+		GuildID:  i.GuildID,
+		QueuedAt: time.Now(),
+	}
+	entry.Body += "\n\n" + submissionMarker(entry.ID)
+
+	if existing, added := outbox.Add(entry); !added {
+		log.Printf("Ignoring repeated submission %s", entry.ID)
+		editDeferredResponse(s, i, alreadySubmittedMessage(existing))
+		return
+	}
+
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+	message, _ := deliverReport(ctx, s, entry)
+	editDeferredResponse(s, i, message)
+}
+
+// submissionID identifies a report in the outbox: the interaction it was
+// started with, or for reports from the legacy modal, which has none, a hash
+// of the report so submitting it again is recognized
+func submissionID(state *ModalState, body string) string {
+	if state.InteractionID != "" {
+		return state.InteractionID
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{state.UserID, state.Command, state.ChannelID, state.Owner + "/" + state.Repo, state.Title, body}, "\x00")))
+	return "report-" + hex.EncodeToString(sum[:])[:16]
+}
+
+// deliverReport makes an attempt at filing a queued report and returns the
+// message for the reporter. done is false when the report will be retried.
+func deliverReport(ctx context.Context, s *discordgo.Session, entry *OutboxEntry) (message string, done bool) {
+	state := entry.State
+	issue, err := createReportIssue(ctx, entry)
+	if err != nil {
+		log.Printf("Failed to create %s issue for submission %s (attempt %d): %v", state.Tracker.Name(), entry.ID, entry.Attempts+1, err)
+
+		retryAfter, retryable := tracker.Retryable(err)
+		if retryable && entry.Attempts+1 < outboxMaxAttempts {
+			outbox.Retry(entry.ID, err, time.Now().Add(outboxBackoff(entry.Attempts+1, retryAfter)))
+			return fmt.Sprintf("⏳ %s isn't accepting issues right now, so your report was queued and will be filed automatically. "+
+				"You'll be notified once it is.", state.Tracker.Name()), false
+		}
+
+		outbox.Remove(entry.ID)
+		if drafts.Save(state) {
+			return "❌ Failed to create issue. Your answers were saved, use /drafts to try again later.", true
+		}
+		return "❌ Failed to create issue. Please try again later.", true
+	}
+
+	outbox.Delivered(entry.ID, issue)
+	return finishReport(ctx, s, entry.GuildID, state, entry.Body, issue), true
+}
+
+// createReportIssue files a report. When an earlier attempt may have created
+// the issue before failing or the bot stopping, the issue is looked up by its
+// submission marker first, so it isn't filed twice.
+func createReportIssue(ctx context.Context, entry *OutboxEntry) (*github.IssueResponse, error) {
+	state := entry.State
+	client := issueTracker(state.Tracker)
+	if entry.Attempted {
+		issue, err := client.FindIssueByMarker(ctx, state.Owner, state.Repo, entry.QueuedAt.Add(-markerClockSkew), submissionMarker(entry.ID))
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			log.Printf("Found issue #%d filed by an earlier attempt at submission %s", issue.Number, entry.ID)
+			return issue, nil
+		}
+	}
+
+	outbox.Attempting(entry.ID)
+	return client.CreateIssueFromRequest(ctx, state.Owner, state.Repo, state.issueRequest(entry.Body))
+}
+
+// submissionMarker is added to the body of every filed report, hidden from
+// readers, to find the issue again when the response to creating it was lost
+func submissionMarker(id string) string {
+	return fmt.Sprintf("<!-- discord-submission: %s -->", id)
+}
+
+// outboxBackoff returns how long to wait after a failed attempt: 30 seconds
+// doubling with every attempt, or longer when GitHub asked for it
+func outboxBackoff(attempts int, retryAfter time.Duration) time.Duration {
+	delay := min(30*time.Second<<min(attempts-1, 10), outboxMaxBackoff)
+	return max(delay, retryAfter)
+}
+
+// alreadySubmittedMessage answers a report that was submitted before
+func alreadySubmittedMessage(entry *OutboxEntry) string {
+	if entry.Issue != nil {
+		return fmt.Sprintf("✅ This report was already filed as issue #%d:\n%s", entry.Issue.Number, entry.Issue.HTMLURL)
+	}
+	return "⏳ This report was already submitted and is queued to be filed. You'll be notified once it is."
+}
+
+// StartOutbox retries queued reports that are due every interval until the
+// context is cancelled. Reporters are notified once their report is filed
+// or given up on, and filed reports are forgotten after a day.
+func StartOutbox(ctx context.Context, s *discordgo.Session, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deliverDue(s)
+				if evicted := outbox.EvictDelivered(time.Now()); evicted > 0 {
+					log.Printf("Removed %d filed reports from the outbox", evicted)
+				}
+			}
+		}
+	}()
+}
+
+// deliverDue makes an attempt at every queued report that is due. Shutdown
+// waits for the attempts in progress.
+func deliverDue(s *discordgo.Session) {
+	if !work.start() {
+		return
+	}
+	defer work.done()
+
+	for _, entry := range outbox.ClaimDue(time.Now()) {
+		ctx, cancel := backgroundContext()
+		message, done := deliverReport(ctx, s, entry)
+		cancel()
+		if done {
+			notifySubmitter(s, entry, message)
+		}
+	}
+}
+
+// notifySubmitter follows up on the interaction that submitted a report, or
+// sends the reporter a DM once the interaction can't be followed up on or
+// the bot restarted, forgetting its token
+func notifySubmitter(s *discordgo.Session, entry *OutboxEntry, message string) {
+	if entry.Token != "" && time.Since(entry.QueuedAt) < followUpWindow {
+		_, err := s.FollowupMessageCreate(&discordgo.Interaction{AppID: entry.AppID, Token: entry.Token}, false,
+			&discordgo.WebhookParams{Content: message, Flags: discordgo.MessageFlagsEphemeral})
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to follow up on submission %s, sending a DM instead: %v", entry.ID, err)
+	}
+
+	channel, err := s.UserChannelCreate(entry.State.UserID)
+	if err != nil {
+		log.Printf("Failed to open DM for submission %s: %v", entry.ID, err)
+		return
+	}
+	if _, err := s.ChannelMessageSend(channel.ID, message); err != nil {
+		log.Printf("Failed to send DM for submission %s: %v", entry.ID, err)
+	}
+}
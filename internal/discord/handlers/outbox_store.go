@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+// outboxRetention is how long filed reports are remembered, so the same
+// submission isn't filed twice
+const outboxRetention = 24 * time.Hour
+
+// OutboxEntry is a submitted report and its progress towards becoming a
+// GitHub issue
+type OutboxEntry struct {
+	// ID is the ID of the interaction the report was started with. A report
+	// is filed at most once per ID.
+	ID    string      `json:"id"`
+	State *ModalState `json:"state"`
+	Body  string      `json:"body"`
+	// AppID, Token and GuildID are of the interaction that submitted the
+	// report, used to follow up once it's filed. The token grants access to
+	// the interaction, so it's only kept in memory.
+	AppID    string    `json:"app_id"`
+	Token    string    `json:"-"`
+	GuildID  string    `json:"guild_id"`
+	QueuedAt time.Time `json:"queued_at"`
+
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error,omitempty"`
+	// Attempted is set before the first request to create the issue, which
+	// may succeed even when no response arrives
+	Attempted bool `json:"attempted,omitempty"`
+
+	// Issue is set once the report has been filed
+	Issue       *github.IssueResponse `json:"issue,omitempty"`
+	DeliveredAt time.Time             `json:"delivered_at,omitempty"`
+
+	// sending is set while an attempt is in progress
+	sending bool
+}
+
+// Outbox holds reports from the moment they're submitted until GitHub
+// accepted them, so they can be retried when GitHub is unavailable
+type Outbox struct {
+	mu      sync.Mutex
+	entries map[string]*OutboxEntry
+	// persist is called with the lock held after every change
+	persist func(map[string]*OutboxEntry)
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{entries: make(map[string]*OutboxEntry)}
+}
+
+// NewFileOutbox returns an Outbox that is written to a JSON file on every
+// change, so queued reports survive a restart
+func NewFileOutbox(path string) (*Outbox, error) {
+	outbox := NewOutbox()
+	if err := store.ReadJSON(path, &outbox.entries); err != nil {
+		return nil, err
+	}
+	if outbox.entries == nil {
+		outbox.entries = make(map[string]*OutboxEntry)
+	}
+
+	outbox.persist = func(entries map[string]*OutboxEntry) {
+		if err := store.WriteJSON(path, entries); err != nil {
+			log.Printf("Failed to save outbox: %v", err)
+		}
+	}
+
+	log.Printf("Loaded %d outbox entries from %s", len(outbox.entries), path)
+	return outbox, nil
+}
+
+// Add queues an entry and claims it for an immediate attempt. When an entry
+// with the same ID exists, a copy of it is returned instead, with false.
+func (o *Outbox) Add(entry *OutboxEntry) (*OutboxEntry, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if existing, ok := o.entries[entry.ID]; ok {
+		return existing.clone(), false
+	}
+
+	entry.NextAttempt = entry.QueuedAt
+	stored := entry.clone()
+	stored.sending = true
+	o.entries[entry.ID] = stored
+	o.changed()
+	return entry, true
+}
+
+// ClaimDue returns copies of the unfiled entries whose next attempt is due,
+// oldest first, and claims them so they aren't attempted twice at once
+func (o *Outbox) ClaimDue(now time.Time) []*OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	due := make([]*OutboxEntry, 0)
+	for _, entry := range o.entries {
+		if entry.Issue == nil && !entry.sending && !entry.NextAttempt.After(now) {
+			entry.sending = true
+			due = append(due, entry.clone())
+		}
+	}
+	sort.Slice(due, func(a, b int) bool {
+		return due[a].QueuedAt.Before(due[b].QueuedAt)
+	})
+	return due
+}
+
+// Retry records a failed attempt and schedules the next one
+func (o *Outbox) Retry(id string, err error, next time.Time) {
+	o.update(id, func(entry *OutboxEntry) {
+		entry.Attempts++
+		entry.LastError = err.Error()
+		entry.NextAttempt = next
+	})
+}
+
+// Attempting records that an attempt is about to create the issue, keeping
+// the claim on the entry
+func (o *Outbox) Attempting(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[id]
+	if !ok || entry.Attempted {
+		return
+	}
+	entry.Attempted = true
+	o.changed()
+}
+
+// Delivered records the issue a report was filed as
+func (o *Outbox) Delivered(id string, issue *github.IssueResponse) {
+	o.update(id, func(entry *OutboxEntry) {
+		entry.Attempts++
+		entry.Issue = issue
+		entry.DeliveredAt = time.Now()
+	})
+}
+
+// Remove drops an entry that won't be filed
+func (o *Outbox) Remove(id string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.entries[id]; !ok {
+		return
+	}
+	delete(o.entries, id)
+	o.changed()
+}
+
+// EvictDelivered removes entries filed longer ago than the retention period
+// and returns how many were removed
+func (o *Outbox) EvictDelivered(now time.Time) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	evicted := 0
+	for id, entry := range o.entries {
+		if entry.Issue != nil && now.Sub(entry.DeliveredAt) > outboxRetention {
+			delete(o.entries, id)
+			evicted++
+		}
+	}
+
+	if evicted > 0 {
+		o.changed()
+	}
+	return evicted
+}
+
+// update changes an entry and releases the claim on it
+func (o *Outbox) update(id string, change func(*OutboxEntry)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[id]
+	if !ok {
+		return
+	}
+	change(entry)
+	entry.sending = false
+	o.changed()
+}
+
+func (o *Outbox) changed() {
+	if o.persist != nil {
+		o.persist(o.entries)
+	}
+}
+
+func (e *OutboxEntry) clone() *OutboxEntry {
+	clone := *e
+	clone.State = e.State.clone()
+	if e.Issue != nil {
+		issue := *e.Issue
+		clone.Issue = &issue
+	}
+	return &clone
+}
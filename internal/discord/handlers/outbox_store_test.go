@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+func newTestOutboxEntry(id string, queuedAt time.Time) *OutboxEntry {
+	state := newTestState()
+	state.UserID = "user1"
+	return &OutboxEntry{ID: id, State: state, Body: "It crashes", QueuedAt: queuedAt}
+}
+
+func TestOutbox_DeduplicatesByID(t *testing.T) {
+	outbox := NewOutbox()
+	now := time.Now()
+
+	if _, added := outbox.Add(newTestOutboxEntry("interaction1", now)); !added {
+		t.Fatal("Add() of a new entry returned false")
+	}
+	if _, added := outbox.Add(newTestOutboxEntry("interaction1", now)); added {
+		t.Error("Add() of a repeated submission returned true")
+	}
+
+	outbox.Delivered("interaction1", &github.IssueResponse{Number: 7})
+	existing, added := outbox.Add(newTestOutboxEntry("interaction1", now))
+	if added || existing.Issue == nil || existing.Issue.Number != 7 {
+		t.Errorf("Add() after delivery = %+v, %v, want the filed entry", existing, added)
+	}
+}
+
+func TestOutbox_ClaimDue(t *testing.T) {
+	outbox := NewOutbox()
+	now := time.Now()
+
+	outbox.Add(newTestOutboxEntry("sending", now))
+	outbox.Add(newTestOutboxEntry("later", now))
+	outbox.Retry("later", errors.New("502"), now.Add(time.Minute))
+	outbox.Add(newTestOutboxEntry("due", now.Add(-time.Minute)))
+	outbox.Retry("due", errors.New("502"), now)
+	outbox.Add(newTestOutboxEntry("filed", now))
+	outbox.Delivered("filed", &github.IssueResponse{Number: 1})
+
+	due := outbox.ClaimDue(now)
+	if len(due) != 1 || due[0].ID != "due" || due[0].Attempts != 1 || due[0].LastError != "502" {
+		t.Fatalf("ClaimDue() = %+v, want only the due entry", due)
+	}
+	if again := outbox.ClaimDue(now); len(again) != 0 {
+		t.Errorf("ClaimDue() returned %d claimed entries again", len(again))
+	}
+	if later := outbox.ClaimDue(now.Add(time.Minute)); len(later) != 1 || later[0].ID != "later" {
+		t.Errorf("ClaimDue() a minute later = %+v, want the retried entry", later)
+	}
+}
+
+func TestOutbox_EvictDelivered(t *testing.T) {
+	outbox := NewOutbox()
+	outbox.Add(newTestOutboxEntry("filed", time.Now()))
+	outbox.Delivered("filed", &github.IssueResponse{Number: 1})
+	outbox.Add(newTestOutboxEntry("queued", time.Now()))
+
+	if evicted := outbox.EvictDelivered(time.Now()); evicted != 0 {
+		t.Errorf("EvictDelivered() = %d, want 0 within the retention", evicted)
+	}
+	if evicted := outbox.EvictDelivered(time.Now().Add(outboxRetention + time.Minute)); evicted != 1 {
+		t.Errorf("EvictDelivered() = %d, want 1 after the retention", evicted)
+	}
+}
+
+func TestFileOutbox_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+
+	outbox, err := NewFileOutbox(path)
+	if err != nil {
+		t.Fatalf("NewFileOutbox() unexpected error: %v", err)
+	}
+	entry := newTestOutboxEntry("interaction1", time.Now())
+	entry.Token = "interaction-token"
+	outbox.Add(entry)
+
+	reloaded, err := NewFileOutbox(path)
+	if err != nil {
+		t.Fatalf("NewFileOutbox() reload unexpected error: %v", err)
+	}
+
+	// Attempts in progress when the bot stopped are due again
+	due := reloaded.ClaimDue(time.Now())
+	if len(due) != 1 || due[0].State.Title != "[Bug]: App crashes" || due[0].Body != "It crashes" {
+		t.Errorf("ClaimDue() after reload = %+v, want the queued report", due)
+	}
+	if len(due) == 1 && due[0].Token != "" {
+		t.Error("ClaimDue() after reload kept the interaction token, want it only in memory")
+	}
+}
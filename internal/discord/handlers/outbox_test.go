@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts   int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 9, want: outboxMaxBackoff},
+		{attempts: 1, retryAfter: 5 * time.Minute, want: 5 * time.Minute},
+		{attempts: 4, retryAfter: time.Second, want: 4 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts, tt.retryAfter); got != tt.want {
+			t.Errorf("outboxBackoff(%d, %v) = %v, want %v", tt.attempts, tt.retryAfter, got, tt.want)
+		}
+	}
+}
+
+// useTestGithub points GithubClient at a fake GitHub API for the test
+func useTestGithub(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient("token")
+	if err := client.SetAPIURL(server.URL); err != nil {
+		t.Fatalf("SetAPIURL() unexpected error: %v", err)
+	}
+	previous := GithubClient
+	GithubClient = client
+	t.Cleanup(func() { GithubClient = previous })
+}
+
+// useTestStores replaces the outbox, drafts and issue records for the test
+func useTestStores(t *testing.T) {
+	t.Helper()
+
+	previousOutbox, previousDrafts, previousIssues := outbox, drafts, issues
+	outbox = NewOutbox()
+	drafts = NewDraftStore(time.Hour)
+	issues = store.NewIssueStore()
+	t.Cleanup(func() { outbox, drafts, issues = previousOutbox, previousDrafts, previousIssues })
+}
+
+func TestDeliverReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		retryAfter  string
+		wantDone    bool
+		wantMessage string
+		wantQueued  bool
+		wantDraft   bool
+		wantRecord  bool
+	}{
+		{name: "created", status: http.StatusCreated, wantDone: true, wantMessage: "✅ Issue #42", wantQueued: true, wantRecord: true},
+		{name: "server error", status: http.StatusBadGateway, wantMessage: "⏳", wantQueued: true},
+		{name: "rejected", status: http.StatusUnprocessableEntity, wantDone: true, wantMessage: "/drafts", wantDraft: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestStores(t)
+			useTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusCreated {
+					fmt.Fprint(w, `{"number": 42, "html_url": "https://github.com/meshtastic/firmware/issues/42"}`)
+				} else {
+					fmt.Fprint(w, `{"message": "failed"}`)
+				}
+			})
+
+			entry := newTestOutboxEntry("interaction1", time.Now())
+			entry.State.Owner, entry.State.Repo = "meshtastic", "firmware"
+			outbox.Add(entry)
+
+			message, done := deliverReport(context.Background(), nil, entry)
+			if done != tt.wantDone || !strings.Contains(message, tt.wantMessage) {
+				t.Errorf("deliverReport() = %q, %v, want a message containing %q, %v", message, done, tt.wantMessage, tt.wantDone)
+			}
+
+			_, added := outbox.Add(newTestOutboxEntry("interaction1", time.Now()))
+			if queued := !added; queued != tt.wantQueued {
+				t.Errorf("entry kept in the outbox = %v, want %v", queued, tt.wantQueued)
+			}
+			if _, ok := drafts.Get("user1", draftID(entry.State)); ok != tt.wantDraft {
+				t.Errorf("draft saved = %v, want %v", ok, tt.wantDraft)
+			}
+			if _, ok := issues.Get("meshtastic", "firmware", 42); ok != tt.wantRecord {
+				t.Errorf("issue recorded = %v, want %v", ok, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestDeliverReport_HonorsRetryAfter(t *testing.T) {
+	useTestStores(t)
+	useTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	entry := newTestOutboxEntry("interaction1", time.Now())
+	outbox.Add(entry)
+	if _, done := deliverReport(context.Background(), nil, entry); done {
+		t.Fatal("deliverReport() gave up on a retryable error")
+	}
+
+	if due := outbox.ClaimDue(time.Now().Add(59 * time.Minute)); len(due) != 0 {
+		t.Errorf("ClaimDue() before Retry-After elapsed = %d entries, want none", len(due))
+	}
+	if due := outbox.ClaimDue(time.Now().Add(61 * time.Minute)); len(due) != 1 {
+		t.Errorf("ClaimDue() after Retry-After elapsed = %d entries, want 1", len(due))
+	}
+}
+
+func TestDeliverReport_FindsEarlierAttempt(t *testing.T) {
+	useTestStores(t)
+	created := 0
+	useTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			created++
+			w.WriteHeader(http.StatusBadGateway)
+		case http.MethodGet:
+			fmt.Fprintf(w, `[{"number": 41, "body": "other report", "created_at": %q},
+				{"number": 42, "html_url": "https://github.com/meshtastic/firmware/issues/42", "body": "report\n\n<!-- discord-submission: interaction1 -->", "created_at": %q}]`,
+				time.Now().Format(time.RFC3339), time.Now().Format(time.RFC3339))
+		}
+	})
+
+	entry := newTestOutboxEntry("interaction1", time.Now())
+	entry.State.Owner, entry.State.Repo = "meshtastic", "firmware"
+	outbox.Add(entry)
+	if _, done := deliverReport(context.Background(), nil, entry); done {
+		t.Fatal("deliverReport() gave up on a server error")
+	}
+
+	retries := outbox.ClaimDue(time.Now().Add(time.Hour))
+	if len(retries) != 1 || !retries[0].Attempted {
+		t.Fatalf("ClaimDue() = %+v, want the attempted entry", retries)
+	}
+	message, done := deliverReport(context.Background(), nil, retries[0])
+	if !done || !strings.Contains(message, "✅ Issue #42") {
+		t.Errorf("deliverReport() = %q, %v, want the issue filed by the first attempt", message, done)
+	}
+	if created != 1 {
+		t.Errorf("issue created %d times, want once", created)
+	}
+}
+
+func TestSubmissionID(t *testing.T) {
+	state := newTestState()
+	state.InteractionID = "interaction1"
+	if got := submissionID(state, "It crashes"); got != "interaction1" {
+		t.Errorf("submissionID() = %q, want the interaction ID", got)
+	}
+
+	// Reports from the legacy modal are identified by their content
+	state.InteractionID = ""
+	first, again := submissionID(state, "It crashes"), submissionID(state.clone(), "It crashes")
+	if first != again {
+		t.Errorf("submissionID() of the same report = %q and %q, want them equal", first, again)
+	}
+	if other := submissionID(state, "It hangs"); other == first {
+		t.Errorf("submissionID() of different reports = %q for both", first)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// reportMessageTitleLength is the length of an issue title taken from a message
+	reportMessageTitleLength = 80
+	// maxTextInputValue is the length Discord allows for a text input's value
+	maxTextInputValue = 4000
+)
+
+// handleReportMessage starts the bug flow from the "Report as bug" message
+// context menu, prefilling the description with the message and crediting
+// its author. The title is the message's first line, which can be changed on
+// the review screen.
+func handleReportMessage(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	if data.Resolved == nil || data.Resolved.Messages[data.TargetID] == nil {
+		log.Printf("Report as bug used without a resolved message: %s", data.TargetID)
+		respondEphemeral(s, i, "❌ Couldn't read that message, please try again.")
+		return
+	}
+	message := data.Resolved.Messages[data.TargetID]
+
+	state, err := newReportState(i, "bug", messageTitle(message.Content))
+	if err != nil {
+		log.Printf("Error getting modal fields: %v", err)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "Sorry, the bug report command is not configured for this channel.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	author := "unknown user"
+	if message.Author != nil {
+		author = message.Author.Username
+	}
+	link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", i.GuildID, message.ChannelID, message.ID)
+	state.Credit = fmt.Sprintf("Originally posted on Discord by: %s (%s)", author, link)
+
+	if field := descriptionField(state.AllFields); field != nil {
+		state.SubmittedValues[field.CustomID] = quoteMessage(message, author, link, field.MaxLength)
+	}
+
+	startReport(s, i, state)
+}
+
+// descriptionField returns the first paragraph text field of a report, or the
+// first text field when there is none
+func descriptionField(fields []config.FieldConfig) *config.FieldConfig {
+	var first *config.FieldConfig
+	for index := range fields {
+		field := &fields[index]
+		if field.IsSelect() {
+			continue
+		}
+		if field.Style == "paragraph" {
+			return field
+		}
+		if first == nil {
+			first = field
+		}
+	}
+	return first
+}
+
+// messageTitle uses the first line of a message as an issue title
+func messageTitle(content string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	return truncateText(strings.TrimSpace(firstLine), reportMessageTitleLength)
+}
+
+// quoteMessage quotes a message for a report's description, followed by who
+// posted it, when and where. The text is cut to fit within the field's
+// maximum length.
+func quoteMessage(message *discordgo.Message, author, link string, maxLength int) string {
+	if maxLength <= 0 || maxLength > maxTextInputValue {
+		maxLength = maxTextInputValue
+	}
+
+	footer := fmt.Sprintf("\n\nPosted by %s on %s\n%s",
+		author, message.Timestamp.UTC().Format(time.RFC1123), link)
+
+	// Leave room for the footer and the "> " added to each line
+	content := strings.TrimSpace(message.Content)
+	budget := maxLength - len([]rune(footer)) - 2*(strings.Count(content, "\n")+1)
+	if budget < len("...") {
+		content = ""
+	} else {
+		content = truncateText(content, budget)
+	}
+
+	if content == "" {
+		return strings.TrimPrefix(footer, "\n\n")
+	}
+
+	lines := strings.Split(content, "\n")
+	for index, line := range lines {
+		lines[index] = "> " + line
+	}
+	return strings.Join(lines, "\n") + footer
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestMessageTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"first line used", "Node reboots every hour\nIt started after 2.5", "Node reboots every hour"},
+		{"whitespace trimmed", "  \n  GPS lost  \n", "GPS lost"},
+		{"long line truncated", strings.Repeat("a", 100), strings.Repeat("a", 77) + "..."},
+		{"empty message", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := messageTitle(tt.content); result != tt.expected {
+				t.Errorf("messageTitle(%q) = %q, want %q", tt.content, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestQuoteMessage(t *testing.T) {
+	message := &discordgo.Message{
+		Content:   "It crashes\nevery time",
+		Timestamp: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+	}
+	link := "https://discord.com/channels/1/2/3"
+
+	expected := "> It crashes\n> every time\n\nPosted by alice on Wed, 01 May 2024 12:30:00 UTC\n" + link
+	if result := quoteMessage(message, "alice", link, 4000); result != expected {
+		t.Errorf("quoteMessage() = %q, want %q", result, expected)
+	}
+
+	message.Content = strings.Repeat("x", 5000)
+	if result := quoteMessage(message, "alice", link, 200); len([]rune(result)) > 200 || !strings.HasSuffix(result, link) {
+		t.Errorf("quoteMessage() with a long message = %d characters, want at most 200 ending with the link", len([]rune(result)))
+	}
+
+	message.Content = ""
+	if result := quoteMessage(message, "alice", link, 4000); strings.HasPrefix(result, ">") {
+		t.Errorf("quoteMessage() with an empty message = %q, want no quote", result)
+	}
+}
+
+func TestDescriptionField(t *testing.T) {
+	fields := []config.FieldConfig{
+		{CustomID: "platform", Type: "dropdown", Options: []string{"Android"}},
+		{CustomID: "version", Style: "short"},
+		{CustomID: "what", Style: "paragraph"},
+	}
+	if field := descriptionField(fields); field == nil || field.CustomID != "what" {
+		t.Errorf("descriptionField() = %+v, want the paragraph field", field)
+	}
+	if field := descriptionField(fields[:2]); field == nil || field.CustomID != "version" {
+		t.Errorf("descriptionField() = %+v, want the first text field", field)
+	}
+	if field := descriptionField(fields[:1]); field != nil {
+		t.Errorf("descriptionField() = %+v, want nil without text fields", field)
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxEmbedFields is the number of fields Discord allows in an embed
+	maxEmbedFields = 25
+	// maxEmbedFieldValue is the length Discord allows for an embed field value
+	maxEmbedFieldValue = 1024
+	// reviewEmbedBudget keeps the review embed under Discord's 6000 character total
+	reviewEmbedBudget = 5000
+	// maxEditButtons leaves the last of Discord's five action rows for Submit and Cancel
+	maxEditButtons = 20
+	// maxIssueTitle is the length GitHub allows for an issue title
+	maxIssueTitle = 256
+	// titleInputID is the CustomID of the text input in the edit title modal
+	titleInputID = "title"
+)
+
+// showReview responds with every answer in the report and buttons to edit
+// each part, cancel the report or submit it
+func showReview(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey string) {
+	steps := planSteps(state.AllFields)
+
+	components := make([]discordgo.MessageComponent, 0)
+	row := discordgo.ActionsRow{}
+	for index := range steps {
+		if index >= maxEditButtons {
+			log.Printf("Report %s has %d parts, only the first %d can be edited", stateKey, len(steps), maxEditButtons)
+			break
+		}
+		if len(row.Components) == 5 {
+			components = append(components, row)
+			row = discordgo.ActionsRow{}
+		}
+		row.Components = append(row.Components, discordgo.Button{
+			Label:    fmt.Sprintf("Edit part %d", index+1),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("edit_%d_%s", index, stateKey),
+		})
+	}
+	if len(row.Components) > 0 {
+		components = append(components, row)
+	}
+
+	finalRow := discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Submit",
+				Style:    discordgo.SuccessButton,
+				CustomID: fmt.Sprintf("submit_%s", stateKey),
+			},
+			discordgo.Button{
+				Label:    "Edit title",
+				Style:    discordgo.SecondaryButton,
+				CustomID: fmt.Sprintf("edittitle_%s", stateKey),
+			},
+			discordgo.Button{
+				Label:    "Cancel",
+				Style:    discordgo.DangerButton,
+				CustomID: fmt.Sprintf("cancel_%s", stateKey),
+			},
+		},
+	}
+	if len(state.Attachments) > 0 {
+		finalRow.Components = append(finalRow.Components, discordgo.Button{
+			Label:    "Remove attachments",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("clearattach_%s", stateKey),
+		})
+	}
+	components = append(components, finalRow)
+
+	content := "Please review your report before it's submitted."
+	if state.AttachmentLimits != nil {
+		content += fmt.Sprintf("\nTo add screenshots or logs, use /attach (up to %d files).", state.AttachmentLimits.FileLimit())
+	}
+
+	err := respondEphemeralMessage(s, i, &discordgo.InteractionResponseData{
+		Content:    content,
+		Embeds:     []*discordgo.MessageEmbed{buildReviewEmbed(state)},
+		Components: components,
+	})
+	if err != nil {
+		log.Printf("Error responding with review: %v", err)
+	}
+}
+
+// buildReviewEmbed builds an embed with one field per answer, in template
+// order, truncated to fit Discord's embed limits
+func buildReviewEmbed(state *ModalState) *discordgo.MessageEmbed {
+	fields := state.AllFields
+	if len(fields) > maxEmbedFields {
+		fields = fields[:maxEmbedFields]
+	}
+
+	valueLimit := maxEmbedFieldValue
+	if len(fields) > 0 && reviewEmbedBudget/len(fields) < valueLimit {
+		valueLimit = reviewEmbedBudget / len(fields)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:  truncateText(state.Title, 256),
+		Fields: make([]*discordgo.MessageEmbedField, 0, len(fields)),
+	}
+	for _, field := range fields {
+		value := strings.TrimSpace(state.SubmittedValues[field.CustomID])
+		if value == "" {
+			value = "_No response_"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  truncateText(field.Label, 60),
+			Value: truncateText(value, valueLimit),
+		})
+	}
+	if len(state.Attachments) > 0 {
+		names := make([]string, 0, len(state.Attachments))
+		for _, attachment := range state.Attachments {
+			names = append(names, attachment.Filename)
+		}
+		embed.Description = truncateText("📎 "+strings.Join(names, ", "), 500)
+	}
+	if len(state.AllFields) > maxEmbedFields {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d more answers are not shown", len(state.AllFields)-maxEmbedFields),
+		}
+	}
+	return embed
+}
+
+// handleBack shows an earlier step of a report
+// CustomID format: "back_<step>_<stateKey>"
+func handleBack(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+
+	parts := strings.SplitN(strings.TrimPrefix(customID, "back_"), "_", 2)
+	if len(parts) != 2 {
+		log.Printf("Invalid back button CustomID format: %s", customID)
+		return
+	}
+	step, err := strconv.Atoi(parts[0])
+	stateKey := parts[1]
+
+	valid := false
+	state, exists := sessions.Update(stateKey, func(state *ModalState) {
+		if valid = err == nil && step >= 0 && step <= state.Step; valid {
+			state.Step = step
+		}
+	})
+	if !exists || !valid {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	showStep(s, i, state, stateKey)
+}
+
+// handleEdit reopens a part of the report from the review screen
+// CustomID format: "edit_<step>_<stateKey>"
+func handleEdit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+
+	parts := strings.SplitN(strings.TrimPrefix(customID, "edit_"), "_", 2)
+	if len(parts) != 2 {
+		log.Printf("Invalid edit button CustomID format: %s", customID)
+		return
+	}
+	step, err := strconv.Atoi(parts[0])
+	stateKey := parts[1]
+
+	valid := false
+	state, exists := sessions.Update(stateKey, func(state *ModalState) {
+		if valid = err == nil && step >= 0 && step < len(planSteps(state.AllFields)); valid {
+			state.Step = step
+		}
+	})
+	if !exists || !valid {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	showStep(s, i, state, stateKey)
+}
+
+// handleEditTitle opens a modal to change the report's title, prefilled with
+// the current one
+func handleEditTitle(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	state, exists := sessions.Get(stateKey)
+	if !exists || !state.Reviewing {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+
+	field := config.FieldConfig{
+		CustomID:  titleInputID,
+		Label:     "Title",
+		Style:     "short",
+		Required:  true,
+		MaxLength: maxIssueTitle,
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: buildModal(fmt.Sprintf("modal_title_%s", stateKey), "Edit title",
+			[]config.FieldConfig{field}, map[string]string{titleInputID: state.Title}),
+	})
+	if err != nil {
+		log.Printf("Error showing edit title modal: %v", err)
+	}
+}
+
+// handleTitleSubmit changes the report's title and returns to the review
+func handleTitleSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	title := strings.TrimSpace(extractModalFields(i.ModalSubmitData().Components)[titleInputID])
+	state, exists := sessions.Update(stateKey, func(state *ModalState) {
+		if title != "" {
+			state.Title = truncateText(title, maxIssueTitle)
+		}
+	})
+	if !exists || !state.Reviewing {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+	showReview(s, i, state, stateKey)
+}
+
+// handleSubmit creates the issue for a reviewed report, unless similar open
+// issues are found, in which case the user is asked to pick one of them first
+func handleSubmit(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	state, exists := sessions.Get(stateKey)
+	if !exists || !state.Reviewing {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+
+	if err := deferEphemeralResponse(s, i); err != nil {
+		log.Printf("Error deferring interaction response: %v", err)
+		return
+	}
+
+	ctx, cancel := interactionContext(i)
+	defer cancel()
+	if candidates := findDuplicates(ctx, state); len(candidates) > 0 {
+		showDuplicates(s, i, candidates, stateKey)
+		return
+	}
+	createIssueFromState(s, i, state, stateKey)
+}
+
+// handleCancel discards a report and its draft
+func handleCancel(s *discordgo.Session, i *discordgo.InteractionCreate, stateKey string) {
+	if state, exists := sessions.Get(stateKey); exists {
+		drafts.Delete(state.UserID, draftID(state))
+	}
+	sessions.Delete(stateKey)
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    "Report cancelled. Nothing was submitted.",
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to cancel: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+)
+
+func TestBuildReviewEmbed(t *testing.T) {
+	state := &ModalState{
+		Title: "[Bug]: App crashes",
+		AllFields: []config.FieldConfig{
+			{CustomID: "what", Label: "What happened?"},
+			{CustomID: "logs", Label: "Logs"},
+			{CustomID: "field_2", Label: "What happened?"},
+		},
+		SubmittedValues: map[string]string{
+			"what":    "It crashed",
+			"logs":    "  ",
+			"field_2": "Twice",
+		},
+	}
+
+	embed := buildReviewEmbed(state)
+
+	if embed.Title != state.Title {
+		t.Errorf("Title = %q, want %q", embed.Title, state.Title)
+	}
+	expected := []struct{ name, value string }{
+		{"What happened?", "It crashed"},
+		{"Logs", "_No response_"},
+		{"What happened?", "Twice"},
+	}
+	if len(embed.Fields) != len(expected) {
+		t.Fatalf("got %d fields, want %d", len(embed.Fields), len(expected))
+	}
+	for index, want := range expected {
+		if embed.Fields[index].Name != want.name || embed.Fields[index].Value != want.value {
+			t.Errorf("field %d = %q: %q, want %q: %q", index,
+				embed.Fields[index].Name, embed.Fields[index].Value, want.name, want.value)
+		}
+	}
+	if embed.Footer != nil {
+		t.Errorf("unexpected footer %q", embed.Footer.Text)
+	}
+}
+
+func TestBuildReviewEmbed_Limits(t *testing.T) {
+	state := &ModalState{
+		Title:           "Long report",
+		SubmittedValues: make(map[string]string),
+	}
+	for index := 0; index < 30; index++ {
+		field := config.FieldConfig{CustomID: strings.Repeat("f", index+1), Label: "Question"}
+		state.AllFields = append(state.AllFields, field)
+		state.SubmittedValues[field.CustomID] = strings.Repeat("a", 2000)
+	}
+
+	embed := buildReviewEmbed(state)
+
+	if len(embed.Fields) != maxEmbedFields {
+		t.Errorf("got %d fields, want %d", len(embed.Fields), maxEmbedFields)
+	}
+	total := len(embed.Title)
+	for _, field := range embed.Fields {
+		if len([]rune(field.Value)) > maxEmbedFieldValue {
+			t.Errorf("field value has %d characters, over Discord's limit", len([]rune(field.Value)))
+		}
+		total += len(field.Name) + len(field.Value)
+	}
+	if total > 6000 {
+		t.Errorf("embed has %d characters, over Discord's 6000 limit", total)
+	}
+	if embed.Footer == nil || !strings.Contains(embed.Footer.Text, "5 more") {
+		t.Errorf("expected footer about hidden answers, got %+v", embed.Footer)
+	}
+}
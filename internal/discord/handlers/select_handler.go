@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// showSelectStep responds with a select menu for each dropdown or checkboxes
+// field in the current step, followed by a Continue button
+func showSelectStep(s *discordgo.Session, i *discordgo.InteractionCreate, state *ModalState, stateKey, notice string) {
+	steps := planSteps(state.AllFields)
+	step := steps[state.Step]
+
+	var content strings.Builder
+	if notice != "" {
+		content.WriteString(notice + "\n\n")
+	}
+	content.WriteString(fmt.Sprintf("**Part %d of %d** - choose an answer for each question, then click 'Continue'.\n",
+		state.Step+1, len(steps)))
+
+	components := make([]discordgo.MessageComponent, 0, step.End-step.Start+1)
+	for index := step.Start; index < step.End; index++ {
+		field := state.AllFields[index]
+
+		content.WriteString(fmt.Sprintf("\n• **%s**", field.Label))
+		if field.IsCheckboxes() {
+			for _, option := range field.RequiredOptions {
+				content.WriteString(fmt.Sprintf("\n  - You must confirm: %s", option))
+			}
+		} else if field.Required {
+			content.WriteString(" (required)")
+		}
+
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{buildSelectMenu(field, index, stateKey, state.selection(field))},
+		})
+	}
+
+	backStep := state.Step - 1
+	if state.Reviewing {
+		backStep = -1
+	}
+	components = append(components, navigationRow(stateKey, discordgo.Button{
+		Label:    "Continue",
+		Style:    discordgo.PrimaryButton,
+		CustomID: fmt.Sprintf("selectdone_%s", stateKey),
+	}, backStep))
+
+	err := respondEphemeralMessage(s, i, &discordgo.InteractionResponseData{
+		Content:    content.String(),
+		Components: components,
+	})
+	if err != nil {
+		log.Printf("Error responding with select menus: %v", err)
+	}
+}
+
+// buildSelectMenu builds a string select menu for a dropdown or checkboxes
+// field, where checkboxes become a multi-select of acknowledgements. Option
+// values are indexes into the field's options so long labels still fit.
+func buildSelectMenu(field config.FieldConfig, fieldIndex int, stateKey string, selected []string) discordgo.SelectMenu {
+	options := field.Options
+	if len(options) > config.MaxSelectOptions {
+		log.Printf("Dropdown %q has %d options, only the first %d are shown", field.Label, len(options), config.MaxSelectOptions)
+		options = options[:config.MaxSelectOptions]
+	}
+
+	menuOptions := make([]discordgo.SelectMenuOption, 0, len(options))
+	for optionIndex, option := range options {
+		menuOptions = append(menuOptions, discordgo.SelectMenuOption{
+			Label:   truncateText(option, 100),
+			Value:   strconv.Itoa(optionIndex),
+			Default: containsString(selected, option),
+		})
+	}
+
+	minValues := 0
+	if field.IsCheckboxes() {
+		minValues = len(field.RequiredOptions)
+	} else if field.Required {
+		minValues = 1
+	}
+	maxValues := 1
+	if field.Multiple {
+		maxValues = len(menuOptions)
+	}
+	// Discord rejects a menu that needs more selections than it offers
+	minValues = min(minValues, maxValues)
+
+	return discordgo.SelectMenu{
+		MenuType:    discordgo.StringSelectMenu,
+		CustomID:    fmt.Sprintf("select_%d_%s", fieldIndex, stateKey),
+		Placeholder: truncateText(field.Label, 150),
+		MinValues:   &minValues,
+		MaxValues:   maxValues,
+		Options:     menuOptions,
+	}
+}
+
+// handleSelectMenu records the options chosen in a select menu. Required
+// options are checked when the user clicks Continue.
+// CustomID format: "select_<fieldIndex>_<stateKey>"
+func handleSelectMenu(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+
+	parts := strings.SplitN(strings.TrimPrefix(data.CustomID, "select_"), "_", 2)
+	if len(parts) != 2 {
+		log.Printf("Invalid select menu CustomID format: %s", data.CustomID)
+		return
+	}
+	fieldIndex, err := strconv.Atoi(parts[0])
+	stateKey := parts[1]
+
+	state, exists := sessions.Get(stateKey)
+	if err != nil || !exists || fieldIndex < 0 || fieldIndex >= len(state.AllFields) {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+
+	field := state.AllFields[fieldIndex]
+	selected, err := selectedOptions(field, data.Values)
+	if err != nil {
+		log.Printf("Rejected selection for %q: %v", field.Label, err)
+		showSelectStep(s, i, state, stateKey, fmt.Sprintf("⚠️ %v", err))
+		return
+	}
+
+	if _, exists := sessions.Update(stateKey, func(state *ModalState) {
+		state.Selections[field.CustomID] = selected
+	}); !exists {
+		respondSessionExpired(s, i, stateKey)
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+}
+
+// handleSelectDone validates the selections in the current step and moves
+// the report on to the next step
+func handleSelectDone(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	stateKey := strings.TrimPrefix(i.MessageComponentData().CustomID, "selectdone_")
+
+	// The step is checked and completed in one update, so a double click
+	// can't complete it twice
+	var completed bool
+	var invalid error
+	state, exists := updateProgress(stateKey, func(state *ModalState) {
+		steps := planSteps(state.AllFields)
+		if state.Step >= len(steps) || !steps[state.Step].Select {
+			return
+		}
+
+		step := steps[state.Step]
+		for index := step.Start; index < step.End; index++ {
+			field := state.AllFields[index]
+			if invalid = field.ValidateSelection(state.selection(field)); invalid != nil {
+				return
+			}
+		}
+		for index := step.Start; index < step.End; index++ {
+			field := state.AllFields[index]
+			state.SubmittedValues[field.CustomID] = formatSelection(field, state.selection(field))
+		}
+		state.Step++
+		completed = true
+	})
+
+	switch {
+	case !exists:
+		respondSessionExpired(s, i, stateKey)
+	case invalid != nil:
+		showSelectStep(s, i, state, stateKey, fmt.Sprintf("⚠️ %v", invalid))
+	case !completed:
+		// The step was already completed (e.g. a double click)
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredMessageUpdate,
+		})
+	default:
+		advanceReport(s, i, state, stateKey)
+	}
+}
+
+// selectedOptions maps the option indexes sent by Discord back to the
+// field's option labels
+func selectedOptions(field config.FieldConfig, values []string) ([]string, error) {
+	selected := make([]string, 0, len(values))
+	for _, value := range values {
+		index, err := strconv.Atoi(value)
+		if err != nil || index < 0 || index >= len(field.Options) {
+			return nil, fmt.Errorf("%q is not a valid option for %s", value, field.Label)
+		}
+		selected = append(selected, field.Options[index])
+	}
+	return selected, nil
+}
+
+// formatSelection renders selected options the way GitHub's issue forms do:
+// checkboxes as a task list and dropdown options comma separated
+func formatSelection(field config.FieldConfig, selected []string) string {
+	if !field.IsCheckboxes() {
+		return strings.Join(selected, ", ")
+	}
+
+	lines := make([]string, 0, len(field.Options))
+	for _, option := range field.Options {
+		mark := " "
+		if containsString(selected, option) {
+			mark = "x"
+		}
+		lines = append(lines, fmt.Sprintf("- [%s] %s", mark, option))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// containsString reports whether a slice contains the given string
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
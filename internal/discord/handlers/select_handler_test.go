@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+)
+
+func TestSelectedOptions(t *testing.T) {
+	field := config.FieldConfig{
+		Label:   "Platform",
+		Type:    "dropdown",
+		Options: []string{"Android", "iOS", "Web"},
+	}
+
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "indexes mapped to labels",
+			values:   []string{"0", "2"},
+			expected: []string{"Android", "Web"},
+		},
+		{
+			name:     "no values",
+			values:   []string{},
+			expected: []string{},
+		},
+		{
+			name:    "index out of range",
+			values:  []string{"3"},
+			wantErr: true,
+		},
+		{
+			name:    "not an index",
+			values:  []string{"Android"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := selectedOptions(field, tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("selectedOptions(%v) expected error, got nil", tt.values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectedOptions(%v) unexpected error: %v", tt.values, err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("selectedOptions(%v) = %v, want %v", tt.values, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    config.FieldConfig
+		selected []string
+		expected string
+	}{
+		{
+			name:     "dropdown options comma separated",
+			field:    config.FieldConfig{Type: "dropdown", Options: []string{"Android", "iOS", "Web"}},
+			selected: []string{"Android", "Web"},
+			expected: "Android, Web",
+		},
+		{
+			name:     "checkboxes as task list",
+			field:    config.FieldConfig{Type: "checkboxes", Options: []string{"I searched existing issues", "I agree to the Code of Conduct"}},
+			selected: []string{"I agree to the Code of Conduct"},
+			expected: "- [ ] I searched existing issues\n- [x] I agree to the Code of Conduct",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatSelection(tt.field, tt.selected)
+			if result != tt.expected {
+				t.Errorf("formatSelection() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildSelectMenu_RequiredOptionsClamped(t *testing.T) {
+	field := config.FieldConfig{
+		Label:           "Terms",
+		Type:            "checkboxes",
+		Multiple:        true,
+		Options:         make([]string, config.MaxSelectOptions+5),
+		RequiredOptions: make([]string, config.MaxSelectOptions+5),
+	}
+	for index := range field.Options {
+		field.Options[index] = fmt.Sprintf("Option %d", index)
+	}
+	copy(field.RequiredOptions, field.Options)
+
+	menu := buildSelectMenu(field, 0, "bug_1_2", nil)
+	if len(menu.Options) != config.MaxSelectOptions || *menu.MinValues != config.MaxSelectOptions || menu.MaxValues != config.MaxSelectOptions {
+		t.Errorf("buildSelectMenu() has %d options, MinValues %d and MaxValues %d, want %d of each",
+			len(menu.Options), *menu.MinValues, menu.MaxValues, config.MaxSelectOptions)
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+// SessionStore keeps the state of in-progress reports. Get returns a copy,
+// so changes to a state must be made with Update, which can't lose changes
+// made by interactions handled at the same time.
+type SessionStore interface {
+	Get(key string) (*ModalState, bool)
+	Set(key string, state *ModalState)
+	// Update changes a session with the store locked and returns a copy of
+	// the changed state, or false when there is no such session
+	Update(key string, change func(*ModalState)) (*ModalState, bool)
+	Delete(key string)
+	// EvictExpired removes sessions that haven't been updated within the
+	// TTL and returns them
+	EvictExpired() map[string]*ModalState
+}
+
+// sessionEntry is a stored session and when it expires
+type sessionEntry struct {
+	State     *ModalState `json:"state"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+// MemorySessionStore is a SessionStore kept in memory. Sessions expire once
+// they haven't been updated for the TTL.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*sessionEntry
+	// version counts changes, so a snapshot is never written over a newer one
+	version uint64
+
+	// persist is called with a snapshot of the sessions after every change,
+	// without the lock held so interactions don't wait on the disk
+	persist func(map[string]*sessionEntry)
+	// persistMu orders writes, written is the version of the last one
+	persistMu sync.Mutex
+	written   uint64
+}
+
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{
+		ttl:      ttl,
+		sessions: make(map[string]*sessionEntry),
+	}
+}
+
+func (m *MemorySessionStore) Get(key string) (*ModalState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.sessions[key]
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return nil, false
+	}
+	return entry.State.clone(), true
+}
+
+func (m *MemorySessionStore) Set(key string, state *ModalState) {
+	m.mu.Lock()
+	m.sessions[key] = &sessionEntry{
+		State:     state.clone(),
+		ExpiresAt: time.Now().Add(m.ttl),
+	}
+	save := m.changed()
+	m.mu.Unlock()
+
+	save()
+}
+
+func (m *MemorySessionStore) Update(key string, change func(*ModalState)) (*ModalState, bool) {
+	m.mu.Lock()
+	entry, ok := m.sessions[key]
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		m.mu.Unlock()
+		return nil, false
+	}
+	change(entry.State)
+	entry.ExpiresAt = time.Now().Add(m.ttl)
+	updated := entry.State.clone()
+	save := m.changed()
+	m.mu.Unlock()
+
+	save()
+	return updated, true
+}
+
+func (m *MemorySessionStore) Delete(key string) {
+	m.mu.Lock()
+	if _, ok := m.sessions[key]; !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.sessions, key)
+	save := m.changed()
+	m.mu.Unlock()
+
+	save()
+}
+
+func (m *MemorySessionStore) EvictExpired() map[string]*ModalState {
+	m.mu.Lock()
+	now := time.Now()
+	evicted := make(map[string]*ModalState)
+	for key, entry := range m.sessions {
+		if now.After(entry.ExpiresAt) {
+			evicted[key] = entry.State
+			delete(m.sessions, key)
+		}
+	}
+	save := func() {}
+	if len(evicted) > 0 {
+		save = m.changed()
+	}
+	m.mu.Unlock()
+
+	save()
+	return evicted
+}
+
+// changed records a change, called with the lock held. It returns the
+// function that writes a snapshot of the sessions, to be called once the
+// lock is released.
+func (m *MemorySessionStore) changed() func() {
+	if m.persist == nil {
+		return func() {}
+	}
+
+	m.version++
+	version := m.version
+	snapshot := make(map[string]*sessionEntry, len(m.sessions))
+	for key, entry := range m.sessions {
+		snapshot[key] = &sessionEntry{State: entry.State.clone(), ExpiresAt: entry.ExpiresAt}
+	}
+
+	return func() {
+		m.persistMu.Lock()
+		defer m.persistMu.Unlock()
+
+		// A change made later may have been written already
+		if version <= m.written {
+			return
+		}
+		m.persist(snapshot)
+		m.written = version
+	}
+}
+
+// NewFileSessionStore returns a SessionStore that is written to a JSON file
+// on every change, so in-progress reports survive a restart
+func NewFileSessionStore(path string, ttl time.Duration) (*MemorySessionStore, error) {
+	sessionStore := NewMemorySessionStore(ttl)
+	if err := store.ReadJSON(path, &sessionStore.sessions); err != nil {
+		return nil, err
+	}
+	if sessionStore.sessions == nil {
+		sessionStore.sessions = make(map[string]*sessionEntry)
+	}
+
+	sessionStore.persist = func(sessions map[string]*sessionEntry) {
+		if err := store.WriteJSON(path, sessions); err != nil {
+			log.Printf("Failed to save sessions: %v", err)
+		}
+	}
+
+	log.Printf("Loaded %d sessions from %s", len(sessionStore.sessions), path)
+	return sessionStore, nil
+}
+
+// StartSessionEviction removes expired sessions every interval until the
+// context is cancelled. Expired sessions are kept as drafts, and drafts past
+// their retention are removed.
+func StartSessionEviction(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if evicted := sessions.EvictExpired(); len(evicted) > 0 {
+					for _, state := range evicted {
+						drafts.Save(state)
+					}
+					log.Printf("Evicted %d expired sessions", len(evicted))
+				}
+				if evicted := drafts.EvictExpired(); evicted > 0 {
+					log.Printf("Removed %d expired drafts", evicted)
+				}
+			}
+		}
+	}()
+}
+
+// clone returns a copy of the state that can be changed without affecting
+// the stored session
+func (m *ModalState) clone() *ModalState {
+	clone := *m
+	clone.AllFields = slices.Clone(m.AllFields)
+	clone.Labels = slices.Clone(m.Labels)
+	clone.Assignees = slices.Clone(m.Assignees)
+	clone.Attachments = slices.Clone(m.Attachments)
+	clone.SubmittedValues = maps.Clone(m.SubmittedValues)
+	if clone.SubmittedValues == nil {
+		clone.SubmittedValues = make(map[string]string)
+	}
+	clone.Selections = make(map[string][]string, len(m.Selections))
+	for key, selected := range m.Selections {
+		clone.Selections[key] = slices.Clone(selected)
+	}
+	return &clone
+}
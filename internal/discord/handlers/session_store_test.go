@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestState() *ModalState {
+	return &ModalState{
+		Title:           "[Bug]: App crashes",
+		SubmittedValues: map[string]string{"description": "It crashes"},
+		Selections:      map[string][]string{"platform": {"Android"}},
+		Command:         "bug",
+	}
+}
+
+func TestMemorySessionStore_GetReturnsCopy(t *testing.T) {
+	sessionStore := NewMemorySessionStore(time.Minute)
+	sessionStore.Set("bug_1_2", newTestState())
+
+	state, ok := sessionStore.Get("bug_1_2")
+	if !ok {
+		t.Fatal("Get() after Set() returned no session")
+	}
+	state.SubmittedValues["description"] = "changed"
+	state.Selections["platform"][0] = "iOS"
+
+	stored, _ := sessionStore.Get("bug_1_2")
+	if stored.SubmittedValues["description"] != "It crashes" || stored.Selections["platform"][0] != "Android" {
+		t.Errorf("changing a state from Get() modified the stored session: %+v", stored)
+	}
+
+	sessionStore.Delete("bug_1_2")
+	if _, ok := sessionStore.Get("bug_1_2"); ok {
+		t.Error("Get() after Delete() returned a session")
+	}
+}
+
+func TestMemorySessionStore_Update(t *testing.T) {
+	sessionStore := NewMemorySessionStore(time.Minute)
+	sessionStore.Set("bug_1_2", newTestState())
+
+	// Selections made at the same time must all be kept
+	var wg sync.WaitGroup
+	for index := 0; index < 20; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			sessionStore.Update("bug_1_2", func(state *ModalState) {
+				state.Selections[fmt.Sprintf("field%d", index)] = []string{"Option"}
+			})
+		}(index)
+	}
+	wg.Wait()
+
+	state, _ := sessionStore.Get("bug_1_2")
+	if len(state.Selections) != 21 {
+		t.Errorf("Selections after concurrent updates = %d, want 21", len(state.Selections))
+	}
+
+	updated, ok := sessionStore.Update("bug_1_2", func(state *ModalState) { state.Step = 2 })
+	if !ok || updated.Step != 2 {
+		t.Errorf("Update() = %+v, %v, want the changed state", updated, ok)
+	}
+	updated.Step = 5
+	if stored, _ := sessionStore.Get("bug_1_2"); stored.Step != 2 {
+		t.Error("changing the state returned by Update() modified the stored session")
+	}
+
+	if _, ok := sessionStore.Update("feature_1_2", func(*ModalState) { t.Error("change called without a session") }); ok {
+		t.Error("Update() of a missing session returned true")
+	}
+}
+
+func TestMemorySessionStore_Expiry(t *testing.T) {
+	sessionStore := NewMemorySessionStore(-time.Second)
+	sessionStore.Set("bug_1_2", newTestState())
+
+	if _, ok := sessionStore.Get("bug_1_2"); ok {
+		t.Error("Get() returned an expired session")
+	}
+
+	evicted := sessionStore.EvictExpired()
+	if _, ok := evicted["bug_1_2"]; !ok || len(evicted) != 1 {
+		t.Errorf("EvictExpired() = %v, want the expired session", evicted)
+	}
+	if evicted := sessionStore.EvictExpired(); len(evicted) != 0 {
+		t.Errorf("EvictExpired() evicted %d sessions twice", len(evicted))
+	}
+}
+
+func TestMemorySessionStore_Concurrent(t *testing.T) {
+	sessionStore := NewMemorySessionStore(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sessionStore.Set("bug_1_2", newTestState())
+			if state, ok := sessionStore.Get("bug_1_2"); ok {
+				state.Step++
+				sessionStore.Set("bug_1_2", state)
+			}
+			sessionStore.EvictExpired()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMemorySessionStore_PersistsSnapshots(t *testing.T) {
+	sessionStore := NewMemorySessionStore(time.Minute)
+	var written map[string]*sessionEntry
+	var locked bool
+	sessionStore.persist = func(sessions map[string]*sessionEntry) {
+		written = sessions
+		if sessionStore.mu.TryLock() {
+			sessionStore.mu.Unlock()
+		} else {
+			locked = true
+		}
+	}
+
+	sessionStore.Set("bug_1_2", newTestState())
+	if locked {
+		t.Error("persist was called with the store locked")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sessionStore.Update("bug_1_2", func(state *ModalState) { state.Step++ })
+		}()
+	}
+	wg.Wait()
+
+	if entry := written["bug_1_2"]; entry == nil || entry.State.Step != 20 {
+		t.Errorf("last written snapshot = %+v, want the session after every update", entry)
+	}
+
+	// Snapshots don't change with the store
+	sessionStore.persist = nil
+	sessionStore.Update("bug_1_2", func(state *ModalState) { state.Step = 0 })
+	if written["bug_1_2"].State.Step != 20 {
+		t.Error("written snapshot changed with the stored session")
+	}
+}
+
+func TestFileSessionStore_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+
+	sessionStore, err := NewFileSessionStore(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileSessionStore() unexpected error: %v", err)
+	}
+	state := newTestState()
+	state.Step = 2
+	sessionStore.Set("bug_1_2", state)
+	sessionStore.Set("feature_1_2", newTestState())
+	sessionStore.Delete("feature_1_2")
+
+	reloaded, err := NewFileSessionStore(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewFileSessionStore() reload unexpected error: %v", err)
+	}
+
+	restored, ok := reloaded.Get("bug_1_2")
+	if !ok {
+		t.Fatal("Get() after reload returned no session")
+	}
+	if restored.Step != 2 || restored.SubmittedValues["description"] != "It crashes" {
+		t.Errorf("Get() after reload = %+v, want the saved state", restored)
+	}
+	if _, ok := reloaded.Get("feature_1_2"); ok {
+		t.Error("Get() after reload returned a deleted session")
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+)
+
+const (
+	// maxModalFields is the number of text inputs Discord allows in a modal
+	maxModalFields = 5
+	// maxSelectFields is the number of select menus shown in one message,
+	// leaving the last of Discord's five action rows for the Continue button
+	maxSelectFields = 4
+)
+
+// reportStep is one part of a multi-part report: either a modal of text
+// inputs or a message of select menus, covering AllFields[Start:End]
+type reportStep struct {
+	Start  int
+	End    int
+	Select bool
+}
+
+// planSteps groups consecutive fields of the same kind into steps, keeping
+// the template's field order
+func planSteps(fields []config.FieldConfig) []reportStep {
+	steps := make([]reportStep, 0)
+	for index, field := range fields {
+		limit := maxModalFields
+		if field.IsSelect() {
+			limit = maxSelectFields
+		}
+
+		if len(steps) > 0 {
+			last := &steps[len(steps)-1]
+			if last.Select == field.IsSelect() && last.End-last.Start < limit {
+				last.End = index + 1
+				continue
+			}
+		}
+
+		steps = append(steps, reportStep{
+			Start:  index,
+			End:    index + 1,
+			Select: field.IsSelect(),
+		})
+	}
+	return steps
+}
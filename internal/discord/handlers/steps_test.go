@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+)
+
+func TestPlanSteps(t *testing.T) {
+	text := config.FieldConfig{Type: "input"}
+	dropdown := config.FieldConfig{Type: "dropdown"}
+
+	tests := []struct {
+		name     string
+		fields   []config.FieldConfig
+		expected []reportStep
+	}{
+		{
+			name:     "no fields",
+			fields:   []config.FieldConfig{},
+			expected: []reportStep{},
+		},
+		{
+			name:   "text fields split into modals of five",
+			fields: []config.FieldConfig{text, text, text, text, text, text, text},
+			expected: []reportStep{
+				{Start: 0, End: 5},
+				{Start: 5, End: 7},
+			},
+		},
+		{
+			name:   "dropdowns get their own step in template order",
+			fields: []config.FieldConfig{text, dropdown, dropdown, text},
+			expected: []reportStep{
+				{Start: 0, End: 1},
+				{Start: 1, End: 3, Select: true},
+				{Start: 3, End: 4},
+			},
+		},
+		{
+			name:   "dropdowns split into steps of four",
+			fields: []config.FieldConfig{dropdown, dropdown, dropdown, dropdown, dropdown},
+			expected: []reportStep{
+				{Start: 0, End: 4, Select: true},
+				{Start: 4, End: 5, Select: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := planSteps(tt.fields)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("planSteps() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxThreadName is the length Discord allows for a thread name
+	maxThreadName = 100
+	// threadArchiveDuration archives an inactive thread after a week, in minutes
+	threadArchiveDuration = 7 * 24 * 60
+)
+
+// openIssueThread opens a thread in the channel the report came from for
+// follow-up discussion, adds the reporter to it and links it from the
+// issue body. It returns the thread's ID.
+func openIssueThread(ctx context.Context, s *discordgo.Session, guildID string, state *ModalState, issue *github.IssueResponse, body string) (string, error) {
+	thread, err := s.ThreadStart(state.ChannelID, threadName(issue.Number, state.Title),
+		discordgo.ChannelTypeGuildPublicThread, threadArchiveDuration)
+	if err != nil {
+		return "", fmt.Errorf("failed to start thread: %w", err)
+	}
+
+	if err := s.ThreadMemberAdd(thread.ID, state.UserID); err != nil {
+		return thread.ID, fmt.Errorf("failed to add reporter to thread: %w", err)
+	}
+
+	_, err = s.ChannelMessageSend(thread.ID, fmt.Sprintf("<@%s> opened issue #%d, follow-up discussion can go here.\n%s",
+		state.UserID, issue.Number, issue.HTMLURL))
+	if err != nil {
+		return thread.ID, fmt.Errorf("failed to post in thread: %w", err)
+	}
+
+	threadURL := fmt.Sprintf("https://discord.com/channels/%s/%s", guildID, thread.ID)
+	body += fmt.Sprintf("\n\n---\nDiscussion on Discord: %s", threadURL)
+	if err := issueTracker(state.Tracker).EditIssueBody(ctx, state.Owner, state.Repo, issue.Number, body); err != nil {
+		return thread.ID, fmt.Errorf("failed to link thread from issue: %w", err)
+	}
+
+	return thread.ID, nil
+}
+
+// threadName names a thread after an issue's number and title
+func threadName(number int, title string) string {
+	return truncateText(fmt.Sprintf("#%d %s", number, title), maxThreadName)
+}
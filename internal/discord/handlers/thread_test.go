@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThreadName(t *testing.T) {
+	if name := threadName(42, "[Bug]: App crashes"); name != "#42 [Bug]: App crashes" {
+		t.Errorf("threadName() = %q", name)
+	}
+
+	name := threadName(1234, strings.Repeat("a", 200))
+	if len([]rune(name)) != maxThreadName || !strings.HasPrefix(name, "#1234 ") {
+		t.Errorf("threadName() with a long title = %q (%d characters)", name, len([]rune(name)))
+	}
+}
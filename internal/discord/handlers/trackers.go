@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// trackers are the clients of the GitLab and Gitea instances modals file
+// issues in
+var trackers = map[config.Tracker]tracker.IssueTracker{}
+
+// InitializeTrackers sets the clients of the GitLab and Gitea instances
+// modals file issues in
+func InitializeTrackers(clients map[config.Tracker]tracker.IssueTracker) {
+	trackers = clients
+}
+
+// issueTracker returns the client for the tracker a report or issue is on,
+// the GitHub client unless it's a GitLab or Gitea instance
+func issueTracker(t config.Tracker) tracker.IssueTracker {
+	if t.IsGitHub() && GithubClient != nil {
+		return GithubClient
+	}
+	if client, ok := trackers[t]; ok {
+		return client
+	}
+	return missingTracker{t}
+}
+
+// missingTracker fails every call, for reports saved before their tracker
+// was removed from the config, or GitHub when no modal files issues there
+type missingTracker struct {
+	tracker config.Tracker
+}
+
+func (m missingTracker) err() error {
+	return fmt.Errorf("no client for tracker %s", m.tracker)
+}
+
+func (m missingTracker) CreateIssueFromRequest(context.Context, string, string, tracker.IssueRequest) (*tracker.IssueResponse, error) {
+	return nil, m.err()
+}
+
+func (m missingTracker) EditIssueBody(context.Context, string, string, int, string) error {
+	return m.err()
+}
+
+func (m missingTracker) SearchOpenIssues(context.Context, string, string, []string, int) ([]*tracker.IssueSummary, error) {
+	return nil, m.err()
+}
+
+func (m missingTracker) CreateComment(context.Context, string, string, int, string) (string, error) {
+	return "", m.err()
+}
+
+func (m missingTracker) GetIssue(context.Context, string, string, int) (*tracker.IssueDetails, error) {
+	return nil, m.err()
+}
+
+func (m missingTracker) CheckRepository(_ context.Context, owner, repo string, _ []string) *tracker.RepositoryCheck {
+	return &tracker.RepositoryCheck{Owner: owner, Repo: repo, Problems: []string{m.err().Error()}}
+}
+
+func (m missingTracker) FindIssueByMarker(context.Context, string, string, time.Time, string) (*tracker.IssueResponse, error) {
+	return nil, m.err()
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	"github.com/meshtastic/meshtastic-bot/internal/gitlab"
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// useTestTracker registers a client for a fake GitLab instance and returns
+// the tracker reports should name to use it
+func useTestTracker(t *testing.T, handler http.HandlerFunc) config.Tracker {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := gitlab.NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("gitlab.NewClient() unexpected error: %v", err)
+	}
+
+	gitlabTracker := config.Tracker{Kind: config.TrackerGitLab, URL: server.URL}
+	previous := trackers
+	trackers = map[config.Tracker]tracker.IssueTracker{gitlabTracker: client}
+	t.Cleanup(func() { trackers = previous })
+	return gitlabTracker
+}
+
+func TestDeliverReport_GitLab(t *testing.T) {
+	useTestStores(t)
+	useTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected GitHub request %s %s", r.Method, r.URL.Path)
+	})
+	gitlabTracker := useTestTracker(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/community%2Ffirmware/issues" {
+			t.Errorf("unexpected GitLab request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"iid": 7, "web_url": "https://gitlab.example/community/firmware/-/issues/7"}`))
+	})
+
+	entry := newTestOutboxEntry("interaction1", time.Now())
+	entry.State.Owner, entry.State.Repo = "community", "firmware"
+	entry.State.Tracker = gitlabTracker
+	outbox.Add(entry)
+
+	message, done := deliverReport(context.Background(), nil, entry)
+	if !done || !strings.Contains(message, "✅ Issue #7") {
+		t.Fatalf("deliverReport() = %q, %v, want the GitLab issue", message, done)
+	}
+	if _, ok := issues.Get("community", "firmware", 7); ok {
+		t.Error("issues.Get() returned the GitLab issue, want only GitHub issues")
+	}
+	records := issues.ListByUser("user1")
+	if len(records) != 1 || records[0].Tracker != config.TrackerGitLab || records[0].TrackerURL != gitlabTracker.URL {
+		t.Errorf("issue records = %+v, want the issue on GitLab", records)
+	}
+}
+
+func TestIssueTracker_Missing(t *testing.T) {
+	missing := config.Tracker{Kind: config.TrackerGitea, URL: "https://removed.example"}
+	if _, err := issueTracker(missing).GetIssue(context.Background(), "community", "firmware", 1); err == nil {
+		t.Error("GetIssue() on a tracker without a client succeeded, want an error")
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// codePattern matches code blocks and inline code, which are never unfurled
+	codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+	// urlPattern matches links, which are unfurled when they are issue URLs
+	urlPattern = regexp.MustCompile(`https?://[^\s<>]+`)
+	// shorthandPattern matches "#123", "repo#123" and "owner/repo#123" at the
+	// start of a word, so channel mentions like "<#123>" are skipped
+	shorthandPattern = regexp.MustCompile(`(?:^|[\s(\[,;:])((?:[\w.-]+/)?[\w.-]*#\d+)\b`)
+)
+
+// HandleMessageCreate replies to messages in configured channels with an
+// embed for every issue they reference, up to the configured limit
+func HandleMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	unfurl := config.GetUnfurlConfig()
+	if unfurl == nil || GithubClient == nil || m.Author == nil || m.Author.Bot {
+		return
+	}
+	if !work.start() {
+		return
+	}
+	defer work.done()
+	if _, ok := config.GetChannelRepository(m.ChannelID); !ok {
+		return
+	}
+
+	refs := messageIssueRefs(m.Content, m.ChannelID, unfurl.Limit())
+	if len(refs) == 0 {
+		return
+	}
+
+	ctx, cancel := backgroundContext()
+	defer cancel()
+	embeds := make([]*discordgo.MessageEmbed, 0, len(refs))
+	for _, ref := range refs {
+		issue, err := GithubClient.GetIssue(ctx, ref.Owner, ref.Repo, ref.Number)
+		if err != nil {
+			// Numbers in chat often aren't issues, so missing ones are skipped quietly
+			log.Printf("Not unfurling %s: %v", ref, err)
+			continue
+		}
+		embeds = append(embeds, buildCompactIssueEmbed(ref, issue))
+	}
+	if len(embeds) == 0 {
+		return
+	}
+
+	_, err := s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embeds:          embeds,
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Printf("Failed to unfurl issue references: %v", err)
+	}
+}
+
+// messageIssueRefs returns the distinct issues in known repositories
+// referenced in a message, in the order they appear, up to limit
+func messageIssueRefs(content, channelID string, limit int) []github.IssueRef {
+	refs := make([]github.IssueRef, 0, limit)
+	seen := make(map[string]bool)
+	for _, candidate := range issueRefCandidates(content) {
+		if len(refs) >= limit {
+			break
+		}
+
+		ref, err := resolveIssueRef(candidate, channelID)
+		if err != nil || !knownRepository(ref) {
+			continue
+		}
+		key := strings.ToLower(ref.String())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// issueRefCandidates returns the links and "#123"-style shorthands in a
+// message, outside of code, in the order they appear
+func issueRefCandidates(content string) []string {
+	content = codePattern.ReplaceAllStringFunc(content, blank)
+
+	type candidate struct {
+		index int
+		text  string
+	}
+	candidates := make([]candidate, 0)
+	for _, match := range urlPattern.FindAllStringIndex(content, -1) {
+		// Trailing punctuation usually belongs to the sentence, not the link
+		text := strings.TrimRight(content[match[0]:match[1]], ".,;:!?)]'\"")
+		candidates = append(candidates, candidate{match[0], text})
+	}
+	// Links are blanked out so their fragments aren't read as shorthands
+	content = urlPattern.ReplaceAllStringFunc(content, blank)
+	for _, match := range shorthandPattern.FindAllStringSubmatchIndex(content, -1) {
+		candidates = append(candidates, candidate{match[2], content[match[2]:match[3]]})
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].index < candidates[b].index
+	})
+	texts := make([]string, len(candidates))
+	for index, c := range candidates {
+		texts[index] = c.text
+	}
+	return texts
+}
+
+// blank replaces text with spaces, keeping the positions of what follows
+func blank(text string) string {
+	return strings.Repeat(" ", len(text))
+}
+
+// buildCompactIssueEmbed shows an issue's title, state and labels on a
+// couple of lines
+func buildCompactIssueEmbed(ref github.IssueRef, issue *github.IssueDetails) *discordgo.MessageEmbed {
+	details := []string{issueStateText(issue)}
+	if len(issue.Labels) > 0 {
+		details = append(details, "`"+strings.Join(issue.Labels, "` `")+"`")
+	}
+	if issue.Comments > 0 {
+		details = append(details, fmt.Sprintf("💬 %d", issue.Comments))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       truncateText(fmt.Sprintf("#%d %s", issue.Number, issue.Title), 256),
+		URL:         issue.HTMLURL,
+		Description: truncateText(strings.Join(details, " · "), 300),
+		Color:       issueColor(issue),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s/%s", ref.Owner, ref.Repo),
+		},
+	}
+}
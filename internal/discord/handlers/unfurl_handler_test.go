@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+func TestIssueRefCandidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "shorthands and links in order",
+			content:  "See Meshtastic-Android#567 and https://github.com/meshtastic/web/issues/12, also #1234.",
+			expected: []string{"Meshtastic-Android#567", "https://github.com/meshtastic/web/issues/12", "#1234"},
+		},
+		{
+			name:     "owner and repo",
+			content:  "(meshtastic/firmware#3)",
+			expected: []string{"meshtastic/firmware#3"},
+		},
+		{
+			name:     "code is ignored",
+			content:  "run `git show #12` or\n```\n#13\n```",
+			expected: []string{},
+		},
+		{
+			name:     "channel mentions and anchors are ignored",
+			content:  "ask in <#871553714782081024>, abc#def or https://example.com/page#42",
+			expected: []string{"https://example.com/page#42"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueRefCandidates(tt.content); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("issueRefCandidates() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMessageIssueRefs(t *testing.T) {
+	GithubOwner, GithubRepo = "meshtastic", "web"
+	defer func() { GithubOwner, GithubRepo = "", "" }()
+
+	// meshtastic/firmware isn't configured, so it could be a private repository
+	content := "#1 #2 https://github.com/meshtastic/web/issues/1 meshtastic/firmware#3 https://example.com/x#4 #5 #6"
+	expected := []github.IssueRef{
+		{Owner: "meshtastic", Repo: "web", Number: 1},
+		{Owner: "meshtastic", Repo: "web", Number: 2},
+		{Owner: "meshtastic", Repo: "web", Number: 5},
+	}
+
+	if got := messageIssueRefs(content, "999", 3); !reflect.DeepEqual(got, expected) {
+		t.Errorf("messageIssueRefs() = %v, want %v", got, expected)
+	}
+}
+
+func TestBuildCompactIssueEmbed(t *testing.T) {
+	ref := github.IssueRef{Owner: "meshtastic", Repo: "web", Number: 12}
+	issue := &github.IssueDetails{Number: 12, Title: "Map is blank", State: "open", Labels: []string{"bug"}, Comments: 4}
+
+	embed := buildCompactIssueEmbed(ref, issue)
+
+	if embed.Title != "#12 Map is blank" {
+		t.Errorf("title = %q", embed.Title)
+	}
+	if embed.Description != "🟢 Open · `bug` · 💬 4" {
+		t.Errorf("description = %q", embed.Description)
+	}
+	if embed.Footer.Text != "meshtastic/web" || embed.Color != colorOpen {
+		t.Errorf("footer = %q, color = %x", embed.Footer.Text, embed.Color)
+	}
+}
@@ -0,0 +1,56 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/meshtastic/meshtastic-bot/internal/discord/handlers"
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+	"github.com/meshtastic/meshtastic-bot/internal/webhook"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// WebhookHandler returns the handler for GitHub webhooks signed with secret,
+// which notifies reporters. Shutdown waits for the deliveries it's handling.
+func (b *DiscordBot) WebhookHandler(ctx context.Context, secret string) http.Handler {
+	login := ""
+	if b.github != nil {
+		var err error
+		if login, err = b.github.Login(ctx); err != nil {
+			b.logger.Printf("Failed to get the bot's GitHub login, assuming issue authors are the bot: %v", err)
+		}
+	}
+	return handlers.TrackRequests(webhook.NewHandler(secret, b.issues, b, login))
+}
+
+// NotifyReporter posts a message about an issue in its companion thread,
+// mentioning the reporter, or sends it to the reporter as a DM when the
+// issue has no thread. Messages quote GitHub comments, so only the reporter
+// is ever pinged, never roles or @everyone.
+func (b *DiscordBot) NotifyReporter(record store.IssueRecord, message string) error {
+	if record.ThreadID != "" {
+		_, err := b.session.ChannelMessageSendComplex(record.ThreadID, &discordgo.MessageSend{
+			Content:         fmt.Sprintf("<@%s> %s", record.UserID, message),
+			AllowedMentions: &discordgo.MessageAllowedMentions{Users: []string{record.UserID}},
+		})
+		if err == nil {
+			return nil
+		}
+		b.logger.Printf("Failed to post in thread %s, sending a DM instead: %v", record.ThreadID, err)
+	}
+
+	channel, err := b.session.UserChannelCreate(record.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM: %w", err)
+	}
+	_, err = b.session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content:         message,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send DM: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/config"
+	"github.com/meshtastic/meshtastic-bot/internal/github"
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// repositoryRecheckInterval is how often repositories that failed the
+// startup check are checked again
+const repositoryRecheckInterval = 5 * time.Minute
+
+// issueTracker returns the client of the tracker a repository is on
+func (b *DiscordBot) issueTracker(t config.Tracker) tracker.IssueTracker {
+	if t.IsGitHub() {
+		return b.github
+	}
+	return b.trackers[t]
+}
+
+// githubRateLimits returns GitHub's rate limits, none without GitHub
+func (b *DiscordBot) githubRateLimits() []github.RateLimit {
+	if b.github == nil {
+		return nil
+	}
+	return b.github.RateLimits()
+}
+
+// checkRepositories checks every configured repository, then rechecks the
+// ones that failed until they all pass. The bot isn't ready until then.
+func (b *DiscordBot) checkRepositories(ctx context.Context) {
+	pending := config.GetRepositoryLabels()
+	for {
+		failed := pending[:0]
+		for _, repository := range pending {
+			check := b.issueTracker(repository.Tracker).CheckRepository(ctx, repository.Owner, repository.Repo, repository.Labels)
+			b.logger.Printf("%s repository check %s", repository.Tracker.Name(), check)
+			if !check.OK() {
+				failed = append(failed, repository)
+			}
+		}
+		for _, limit := range b.githubRateLimits() {
+			b.logger.Printf("GitHub %s rate limit: %d of %d left, resets %s",
+				limit.Resource, limit.Remaining, limit.Limit, limit.Reset.Format(time.RFC3339))
+		}
+		if len(failed) == 0 {
+			b.logger.Println("All configured repositories passed their checks")
+			b.repositoriesReady.Store(true)
+			return
+		}
+		pending = failed
+
+		b.logger.Printf("%d repositories failed their checks, checking again in %s", len(pending), repositoryRecheckInterval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(repositoryRecheckInterval):
+		}
+	}
+}
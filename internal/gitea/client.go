@@ -0,0 +1,344 @@
+// Package gitea files issues in Gitea and Forgejo repositories through the
+// API v1 they share
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+const (
+	// newLabelColor is the color of labels created for new issues, the same
+	// grey as on GitHub
+	newLabelColor = "#ededed"
+	// pageSize is the number of labels listed per request, Gitea's default
+	// maximum
+	pageSize = 50
+)
+
+// Client implements tracker.IssueTracker for a Gitea or Forgejo instance
+type Client struct {
+	api *tracker.API
+}
+
+var _ tracker.IssueTracker = (*Client)(nil)
+
+// NewClient returns a client for the instance at webURL, e.g.
+// https://codeberg.org, authenticated with an access token
+func NewClient(webURL, token string) (*Client, error) {
+	api, err := tracker.NewAPI("Gitea", strings.TrimRight(webURL, "/")+"/api/v1", http.Header{"Authorization": {"token " + token}})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: api}, nil
+}
+
+// issue is an issue or pull request as returned by the API
+type issue struct {
+	ID       int64  `json:"id"`
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	State    string `json:"state"`
+	HTMLURL  string `json:"html_url"`
+	Comments int    `json:"comments"`
+	User     struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	PullRequest *struct{} `json:"pull_request"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func repoPath(owner, repo string) string {
+	return "repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// CreateIssueFromRequest creates an issue. Gitea takes labels by ID, so
+// missing labels are created first; labels, assignees and milestones that
+// can't be set are logged and skipped.
+func (c *Client) CreateIssueFromRequest(ctx context.Context, owner, repo string, request tracker.IssueRequest) (*tracker.IssueResponse, error) {
+	c.api.Logf("Creating issue in %s/%s", owner, repo)
+	c.api.Logf("Title: %s", request.Title)
+	c.api.Logf("Labels: %v", request.Labels)
+
+	body := map[string]interface{}{
+		"title": request.Title,
+		"body":  request.Body,
+	}
+	if len(request.Labels) > 0 {
+		labelIDs, err := c.labelIDs(ctx, owner, repo, request.Labels)
+		if err != nil {
+			c.api.Logf("Not setting labels: %v", err)
+		} else {
+			body["labels"] = labelIDs
+		}
+	}
+	if len(request.Assignees) > 0 {
+		c.api.Logf("Assignees: %v", request.Assignees)
+		body["assignees"] = request.Assignees
+	}
+	if request.Milestone != "" {
+		id, err := c.findMilestone(ctx, owner, repo, request.Milestone)
+		if err != nil {
+			c.api.Logf("Not setting milestone %q: %v", request.Milestone, err)
+		} else {
+			body["milestone"] = id
+		}
+	}
+
+	var created issue
+	if err := c.api.Do(ctx, http.MethodPost, repoPath(owner, repo)+"/issues", nil, body, &created); err != nil {
+		return nil, err
+	}
+	return &tracker.IssueResponse{Number: created.Number, HTMLURL: created.HTMLURL, ID: created.ID}, nil
+}
+
+// labelIDs returns the IDs of the named labels, creating the missing ones
+func (c *Client) labelIDs(ctx context.Context, owner, repo string, names []string) ([]int64, error) {
+	existing, err := c.listLabels(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		if id, ok := existing[strings.ToLower(name)]; ok {
+			ids = append(ids, id)
+			continue
+		}
+
+		id, err := c.createLabel(ctx, owner, repo, name)
+		if err != nil {
+			c.api.Logf("Not setting label %q: %v", name, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// createLabel creates a label in the default color and returns its ID
+func (c *Client) createLabel(ctx context.Context, owner, repo, name string) (int64, error) {
+	c.api.Logf("Creating label %q in %s/%s", name, owner, repo)
+	var label struct {
+		ID int64 `json:"id"`
+	}
+	err := c.api.Do(ctx, http.MethodPost, repoPath(owner, repo)+"/labels", nil,
+		map[string]string{"name": name, "color": newLabelColor}, &label)
+	return label.ID, err
+}
+
+// listLabels returns the IDs of a repository's labels by lowercased name
+func (c *Client) listLabels(ctx context.Context, owner, repo string) (map[string]int64, error) {
+	ids := make(map[string]int64)
+	for page := 1; ; page++ {
+		var labels []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		err := c.api.Do(ctx, http.MethodGet, repoPath(owner, repo)+"/labels", url.Values{
+			"page":  {strconv.Itoa(page)},
+			"limit": {strconv.Itoa(pageSize)},
+		}, nil, &labels)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			ids[strings.ToLower(label.Name)] = label.ID
+		}
+		if len(labels) < pageSize {
+			return ids, nil
+		}
+	}
+}
+
+// findMilestone returns the ID of a milestone, given its title or ID
+func (c *Client) findMilestone(ctx context.Context, owner, repo, milestone string) (int64, error) {
+	var found struct {
+		ID    int64  `json:"id"`
+		State string `json:"state"`
+	}
+	err := c.api.Do(ctx, http.MethodGet, repoPath(owner, repo)+"/milestones/"+url.PathEscape(milestone), nil, nil, &found)
+	if err != nil {
+		return 0, err
+	}
+	if found.State != "open" {
+		return 0, fmt.Errorf("milestone %q is %s", milestone, found.State)
+	}
+	return found.ID, nil
+}
+
+// EditIssueBody replaces the body of an issue
+func (c *Client) EditIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	c.api.Logf("Editing %s/%s#%d", owner, repo, number)
+
+	path := fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), number)
+	return c.api.Do(ctx, http.MethodPatch, path, nil, map[string]string{"body": body}, nil)
+}
+
+// SearchOpenIssues returns up to limit open issues matching any of the
+// terms, searching for each term on its own
+func (c *Client) SearchOpenIssues(ctx context.Context, owner, repo string, terms []string, limit int) ([]*tracker.IssueSummary, error) {
+	c.api.Logf("Searching issues in %s/%s: %s", owner, repo, strings.Join(terms, " OR "))
+
+	return tracker.SearchEach(terms, limit, func(term string) ([]*tracker.IssueSummary, error) {
+		var found []issue
+		err := c.api.Do(ctx, http.MethodGet, repoPath(owner, repo)+"/issues", url.Values{
+			"state": {"open"},
+			"type":  {"issues"},
+			"q":     {term},
+			"limit": {strconv.Itoa(limit)},
+		}, nil, &found)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries := make([]*tracker.IssueSummary, 0, len(found))
+		for index := range found {
+			summaries = append(summaries, &tracker.IssueSummary{
+				Number:   found[index].Number,
+				Title:    found[index].Title,
+				State:    found[index].State,
+				HTMLURL:  found[index].HTMLURL,
+				Comments: found[index].Comments,
+			})
+		}
+		return summaries, nil
+	})
+}
+
+// CreateComment adds a comment to an issue and returns its URL
+func (c *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (string, error) {
+	c.api.Logf("Commenting on %s/%s#%d", owner, repo, number)
+
+	var comment struct {
+		HTMLURL string `json:"html_url"`
+	}
+	path := fmt.Sprintf("%s/issues/%d/comments", repoPath(owner, repo), number)
+	if err := c.api.Do(ctx, http.MethodPost, path, nil, map[string]string{"body": body}, &comment); err != nil {
+		return "", err
+	}
+	return comment.HTMLURL, nil
+}
+
+// FindIssueByMarker returns the newest issue created since a time whose body
+// contains marker, or nil when there is none
+func (c *Client) FindIssueByMarker(ctx context.Context, owner, repo string, since time.Time, marker string) (*tracker.IssueResponse, error) {
+	c.api.Logf("Looking for %s in %s/%s", marker, owner, repo)
+
+	var found []issue
+	err := c.api.Do(ctx, http.MethodGet, repoPath(owner, repo)+"/issues", url.Values{
+		"state": {"all"},
+		"type":  {"issues"},
+		"since": {since.UTC().Format(time.RFC3339)},
+		"limit": {strconv.Itoa(pageSize)},
+	}, nil, &found)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range found {
+		if !found[index].CreatedAt.Before(since) && strings.Contains(found[index].Body, marker) {
+			return &tracker.IssueResponse{Number: found[index].Number, HTMLURL: found[index].HTMLURL, ID: found[index].ID}, nil
+		}
+	}
+	return nil, nil
+}
+
+// CheckRepository checks that the repository has issues enabled and that the
+// token may label them, creating any of the labels that are missing
+func (c *Client) CheckRepository(ctx context.Context, owner, repo string, labels []string) *tracker.RepositoryCheck {
+	check := &tracker.RepositoryCheck{Owner: owner, Repo: repo}
+	problem := func(format string, args ...interface{}) *tracker.RepositoryCheck {
+		check.Problems = append(check.Problems, fmt.Sprintf(format, args...))
+		return check
+	}
+
+	var repository struct {
+		HasIssues   bool `json:"has_issues"`
+		Archived    bool `json:"archived"`
+		Permissions *struct {
+			Admin bool `json:"admin"`
+			Push  bool `json:"push"`
+		} `json:"permissions"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, repoPath(owner, repo), nil, nil, &repository); err != nil {
+		return problem("repository can't be read: %v", err)
+	}
+	if !repository.HasIssues {
+		return problem("issues are disabled")
+	}
+	if repository.Archived {
+		return problem("repository is archived")
+	}
+	if permissions := repository.Permissions; permissions != nil && !permissions.Admin && !permissions.Push && len(labels) > 0 {
+		problem("labels need write access, but the token only has read access")
+	}
+
+	existing, err := c.listLabels(ctx, owner, repo)
+	if err != nil {
+		return problem("labels can't be listed: %v", err)
+	}
+	for _, label := range labels {
+		if _, ok := existing[strings.ToLower(label)]; ok {
+			continue
+		}
+		if _, err := c.createLabel(ctx, owner, repo, label); err != nil {
+			problem("label %q is missing and couldn't be created: %v", label, err)
+			continue
+		}
+		check.CreatedLabels = append(check.CreatedLabels, label)
+	}
+	return check
+}
+
+// GetIssue returns an issue or pull request
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*tracker.IssueDetails, error) {
+	c.api.Logf("Getting %s/%s#%d", owner, repo, number)
+
+	var found issue
+	path := fmt.Sprintf("%s/issues/%d", repoPath(owner, repo), number)
+	if err := c.api.Do(ctx, http.MethodGet, path, nil, nil, &found); err != nil {
+		return nil, err
+	}
+
+	details := &tracker.IssueDetails{
+		Number:        found.Number,
+		Title:         found.Title,
+		Body:          found.Body,
+		State:         found.State,
+		HTMLURL:       found.HTMLURL,
+		Author:        found.User.Login,
+		Comments:      found.Comments,
+		IsPullRequest: found.PullRequest != nil,
+		CreatedAt:     found.CreatedAt,
+		UpdatedAt:     found.UpdatedAt,
+	}
+	for _, label := range found.Labels {
+		details.Labels = append(details.Labels, label.Name)
+	}
+	for _, assignee := range found.Assignees {
+		details.Assignees = append(details.Assignees, assignee.Login)
+	}
+	if found.Milestone != nil {
+		details.Milestone = found.Milestone.Title
+	}
+	return details, nil
+}
@@ -0,0 +1,179 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// newTestClient returns a client for a fake Gitea instance
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestClient_CreateIssueFromRequest(t *testing.T) {
+	var created map[string]interface{}
+	var createdLabel map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token token" {
+			t.Errorf("Authorization = %q, want token token", got)
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/repos/community/firmware/labels":
+			w.Write([]byte(`[{"id": 1, "name": "Bug"}]`))
+		case "POST /api/v1/repos/community/firmware/labels":
+			json.NewDecoder(r.Body).Decode(&createdLabel)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 2}`))
+		case "GET /api/v1/repos/community/firmware/milestones/2.6":
+			w.Write([]byte(`{"id": 4, "state": "open"}`))
+		case "POST /api/v1/repos/community/firmware/issues":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 500, "number": 8, "html_url": "https://gitea.example/community/firmware/issues/8"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+
+	issue, err := client.CreateIssueFromRequest(context.Background(), "community", "firmware", tracker.IssueRequest{
+		Title:     "Crash",
+		Body:      "It crashed",
+		Labels:    []string{"bug", "from-discord"},
+		Assignees: []string{"alice"},
+		Milestone: "2.6",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssueFromRequest() unexpected error: %v", err)
+	}
+	if issue.Number != 8 || issue.ID != 500 || issue.HTMLURL != "https://gitea.example/community/firmware/issues/8" {
+		t.Errorf("CreateIssueFromRequest() = %+v", issue)
+	}
+
+	if createdLabel["name"] != "from-discord" || createdLabel["color"] != newLabelColor {
+		t.Errorf("created label = %v, want from-discord", createdLabel)
+	}
+	want := map[string]interface{}{
+		"title":     "Crash",
+		"body":      "It crashed",
+		"labels":    []interface{}{float64(1), float64(2)},
+		"assignees": []interface{}{"alice"},
+		"milestone": float64(4),
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("created issue = %v, want %v", created, want)
+	}
+}
+
+func TestClient_GetIssue(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/community/firmware/issues/3" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"number": 3, "title": "Add mesh map", "body": "Body", "state": "closed",
+			"html_url": "https://gitea.example/community/firmware/pulls/3", "comments": 4,
+			"user": {"login": "bob"}, "labels": [{"name": "enhancement"}],
+			"assignees": [{"login": "alice"}], "pull_request": {"merged": true}
+		}`))
+	})
+
+	issue, err := client.GetIssue(context.Background(), "community", "firmware", 3)
+	if err != nil {
+		t.Fatalf("GetIssue() unexpected error: %v", err)
+	}
+	if issue.State != "closed" || !issue.IsPullRequest || issue.Author != "bob" || issue.Comments != 4 {
+		t.Errorf("GetIssue() = %+v", issue)
+	}
+	if !reflect.DeepEqual(issue.Labels, []string{"enhancement"}) || !reflect.DeepEqual(issue.Assignees, []string{"alice"}) {
+		t.Errorf("GetIssue() labels %v and assignees %v", issue.Labels, issue.Assignees)
+	}
+}
+
+func TestClient_EditIssueBody_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method = %s, want PATCH", r.Method)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message": "user does not have permission"}`))
+	})
+
+	err := client.EditIssueBody(context.Background(), "community", "firmware", 3, "Body")
+	var httpErr *tracker.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("EditIssueBody() error = %v, want a 403 HTTPError", err)
+	}
+	if want := "gitea API returned 403: user does not have permission"; err.Error() != want {
+		t.Errorf("EditIssueBody() error = %q, want %q", err, want)
+	}
+}
+
+func TestClient_FindIssueByMarker(t *testing.T) {
+	since := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("since"); got != "2025-03-01T12:00:00Z" {
+			t.Errorf("since = %q", got)
+		}
+		// since filters by update, so older issues that were edited are listed too
+		w.Write([]byte(`[
+			{"number": 9, "body": "Body <!-- marker -->", "created_at": "2025-03-01T11:00:00Z"},
+			{"number": 8, "body": "Body", "created_at": "2025-03-01T12:05:00Z"},
+			{"number": 7, "body": "Body <!-- marker -->", "html_url": "https://codeberg.org/community/firmware/issues/7", "created_at": "2025-03-01T12:01:00Z"}
+		]`))
+	})
+
+	issue, err := client.FindIssueByMarker(context.Background(), "community", "firmware", since, "<!-- marker -->")
+	if err != nil {
+		t.Fatalf("FindIssueByMarker() unexpected error: %v", err)
+	}
+	if issue == nil || issue.Number != 7 {
+		t.Errorf("FindIssueByMarker() = %+v, want #7", issue)
+	}
+}
+
+func TestClient_CheckRepository(t *testing.T) {
+	var created []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/repos/community/firmware":
+			w.Write([]byte(`{"has_issues": true, "permissions": {"push": true}}`))
+		case r.URL.Path == "/api/v1/repos/community/firmware/labels" && r.Method == http.MethodGet:
+			w.Write([]byte(`[{"id": 1, "name": "Bug"}]`))
+		case r.URL.Path == "/api/v1/repos/community/firmware/labels":
+			var label map[string]string
+			json.NewDecoder(r.Body).Decode(&label)
+			created = append(created, label["name"])
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 2}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	check := client.CheckRepository(context.Background(), "community", "firmware", []string{"bug", "from-discord"})
+	if !check.OK() || !reflect.DeepEqual(check.CreatedLabels, []string{"from-discord"}) {
+		t.Errorf("CheckRepository() = %s, want OK with from-discord created", check)
+	}
+	if !reflect.DeepEqual(created, []string{"from-discord"}) {
+		t.Errorf("created labels %v", created)
+	}
+}
@@ -0,0 +1,256 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"golang.org/x/oauth2"
+)
+
+// appJWTLifetime is how long an app JWT is valid, GitHub allows at most 10
+// minutes. JWTs are backdated a minute to allow for clock drift.
+const appJWTLifetime = 9 * time.Minute
+
+// appAuth authenticates as the installations of a GitHub App. Each account
+// the app is installed on gets its own client, whose installation tokens are
+// minted on first use and refreshed before they expire.
+type appAuth struct {
+	appID int64
+	key   *rsa.PrivateKey
+	// client is authenticated as the app itself, with a JWT
+	client *github.Client
+
+	mu sync.Mutex
+	// installations are the installation IDs by lowercased account login
+	installations map[string]int64
+	// lookups are the installation lookups in progress, so concurrent
+	// requests for an account wait for one lookup without holding mu
+	lookups map[string]*installationLookup
+	clients map[int64]*github.Client
+	// limits are shared by the installation clients
+	limits *rateLimits
+}
+
+// NewAppClient creates a client that authenticates as a GitHub App, given
+// its ID and PEM-encoded private key. Issues it creates are authored by the
+// app rather than a person.
+func NewAppClient(appID int64, privateKeyPEM []byte) (*Client, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &appAuth{
+		appID:         appID,
+		key:           key,
+		installations: make(map[string]int64),
+		lookups:       make(map[string]*installationLookup),
+		clients:       make(map[int64]*github.Client),
+		limits:        newRateLimits(),
+	}
+	app.client = github.NewClient(&http.Client{Transport: &jwtTransport{app: app, base: http.DefaultTransport}})
+
+	return &Client{
+		client: app.client,
+		app:    app,
+		limits: app.limits,
+	}, nil
+}
+
+// parsePrivateKey decodes an RSA key in PKCS#1 form, as downloaded from
+// GitHub, or PKCS#8 form
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// jwt signs a JSON Web Token identifying the app
+func (a *appAuth) jwt(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// installationLookup is a lookup of an account's installation, done is
+// closed once id or err is set
+type installationLookup struct {
+	done chan struct{}
+	id   int64
+	err  error
+}
+
+// installationClient returns the client for the installation on an account,
+// looking the installation up through the repository when one is given
+func (a *appAuth) installationClient(ctx context.Context, owner, repo string) (*github.Client, error) {
+	id, err := a.installationID(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if client, ok := a.clients[id]; ok {
+		return client, nil
+	}
+
+	source := oauth2.ReuseTokenSource(nil, &installationTokenSource{app: a, id: id})
+	httpClient := oauth2.NewClient(context.Background(), source)
+	httpClient.Transport = &rateLimitTransport{base: httpClient.Transport, limits: a.limits}
+	client := github.NewClient(httpClient)
+	client.BaseURL, client.UploadURL = a.client.BaseURL, a.client.UploadURL
+	a.clients[id] = client
+	return client, nil
+}
+
+// installationID returns the ID of the installation on an account. Only the
+// first request for an account looks it up; concurrent ones wait for it.
+// Failed lookups aren't remembered, so the next request tries again.
+func (a *appAuth) installationID(ctx context.Context, owner, repo string) (int64, error) {
+	key := strings.ToLower(owner)
+
+	a.mu.Lock()
+	if id, ok := a.installations[key]; ok {
+		a.mu.Unlock()
+		return id, nil
+	}
+	if lookup, ok := a.lookups[key]; ok {
+		a.mu.Unlock()
+		select {
+		case <-lookup.done:
+			return lookup.id, lookup.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	lookup := &installationLookup{done: make(chan struct{})}
+	a.lookups[key] = lookup
+	a.mu.Unlock()
+
+	installation, err := a.findInstallation(ctx, owner, repo)
+	if err == nil {
+		lookup.id = installation.GetID()
+		log.Printf("[GitHub API] Using installation %d of app %d for %s", lookup.id, a.appID, owner)
+	}
+	lookup.err = err
+
+	a.mu.Lock()
+	delete(a.lookups, key)
+	if err == nil {
+		a.installations[key] = lookup.id
+	}
+	a.mu.Unlock()
+	close(lookup.done)
+	return lookup.id, lookup.err
+}
+
+// findInstallation looks up the app's installation for a repository, or for
+// an organization or user account when no repository is given
+func (a *appAuth) findInstallation(ctx context.Context, owner, repo string) (*github.Installation, error) {
+	var installation *github.Installation
+	var resp *github.Response
+	var err error
+	if repo != "" {
+		installation, resp, err = a.client.Apps.FindRepositoryInstallation(ctx, owner, repo)
+	} else {
+		installation, resp, err = a.client.Apps.FindOrganizationInstallation(ctx, owner)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			installation, resp, err = a.client.Apps.FindUserInstallation(ctx, owner)
+		}
+	}
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("app is not installed for %s, github API returned %d: %w", owner, resp.StatusCode, err)
+		}
+		return nil, err
+	}
+	return installation, nil
+}
+
+// installationTokenSource mints installation access tokens, which expire
+// after an hour
+type installationTokenSource struct {
+	app *appAuth
+	id  int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	log.Printf("[GitHub API] Creating token for installation %d", s.id)
+
+	// Token sources aren't given the caller's context, so minting is bounded on its own
+	ctx, cancel := context.WithTimeout(context.Background(), CallTimeout)
+	defer cancel()
+	token, resp, err := s.app.client.Apps.CreateInstallationToken(ctx, s.id, nil)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: token.GetToken(), Expiry: token.GetExpiresAt().Time}, nil
+}
+
+// jwtTransport authenticates requests as the app itself
+type jwtTransport struct {
+	app  *appAuth
+	base http.RoundTripper
+}
+
+func (t *jwtTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.jwt(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign GitHub App JWT: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,200 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testAppKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestAppAuth_JWT(t *testing.T) {
+	key, keyPEM := testAppKey(t)
+	client, err := NewAppClient(1234, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppClient() unexpected error: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	token, err := client.app.jwt(now)
+	if err != nil {
+		t.Fatalf("jwt() unexpected error: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("jwt() = %q, want three parts", token)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hash[:], signature); err != nil {
+		t.Errorf("jwt() signature doesn't verify: %v", err)
+	}
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		IssuedAt  int64  `json:"iat"`
+		ExpiresAt int64  `json:"exp"`
+		Issuer    string `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	if claims.Issuer != "1234" || claims.IssuedAt != now.Unix()-60 || claims.ExpiresAt != now.Add(appJWTLifetime).Unix() {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestNewAppClient_InvalidKey(t *testing.T) {
+	if _, err := NewAppClient(1234, []byte("not a key")); err == nil {
+		t.Error("NewAppClient() accepted a key that isn't PEM encoded")
+	}
+}
+
+func TestAppAuth_InstallationTokens(t *testing.T) {
+	tests := []struct {
+		name           string
+		tokenLifetime  time.Duration
+		expectedTokens int32
+	}{
+		{name: "tokens are reused", tokenLifetime: time.Hour, expectedTokens: 1},
+		{name: "expiring tokens are refreshed", tokenLifetime: 5 * time.Second, expectedTokens: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, keyPEM := testAppKey(t)
+			var lookups, tokens atomic.Int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth := r.Header.Get("Authorization")
+				switch {
+				case r.URL.Path == "/repos/meshtastic/firmware/installation":
+					lookups.Add(1)
+					if strings.Count(auth, ".") != 2 {
+						t.Errorf("installation lookup authorized with %q, want a JWT", auth)
+					}
+					fmt.Fprint(w, `{"id": 42}`)
+				case r.URL.Path == "/app/installations/42/access_tokens":
+					n := tokens.Add(1)
+					expiresAt := time.Now().Add(tt.tokenLifetime).UTC().Format(time.RFC3339)
+					fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, expiresAt)
+				case strings.HasPrefix(r.URL.Path, "/repos/meshtastic/firmware/issues/"):
+					if auth != fmt.Sprintf("Bearer ghs_%d", tokens.Load()) {
+						t.Errorf("issue request authorized with %q, want the latest installation token", auth)
+					}
+					fmt.Fprint(w, `{"number": 7, "title": "Crash"}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewAppClient(1234, keyPEM)
+			if err != nil {
+				t.Fatalf("NewAppClient() unexpected error: %v", err)
+			}
+			client.app.client.BaseURL, _ = url.Parse(server.URL + "/")
+
+			for range 2 {
+				if _, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 7); err != nil {
+					t.Fatalf("GetIssue() unexpected error: %v", err)
+				}
+			}
+
+			if got := lookups.Load(); got != 1 {
+				t.Errorf("looked up the installation %d times, want 1", got)
+			}
+			if got := tokens.Load(); got != tt.expectedTokens {
+				t.Errorf("created %d installation tokens, want %d", got, tt.expectedTokens)
+			}
+		})
+	}
+}
+
+func TestAppAuth_InstallationLookups(t *testing.T) {
+	_, keyPEM := testAppKey(t)
+	var lookups atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/meshtastic/firmware/installation":
+			if lookups.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+			fmt.Fprint(w, `{"id": 42}`)
+		case "/repos/other/firmware/installation":
+			fmt.Fprint(w, `{"id": 43}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	}()
+
+	client, err := NewAppClient(1234, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppClient() unexpected error: %v", err)
+	}
+	client.app.client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	results := make(chan int64, 3)
+	lookup := func() {
+		id, err := client.app.installationID(context.Background(), "meshtastic", "firmware")
+		if err != nil {
+			t.Errorf("installationID() unexpected error: %v", err)
+		}
+		results <- id
+	}
+	go lookup()
+	<-started
+	go lookup()
+	go lookup()
+
+	// Another account is looked up while the first lookup is still running
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if id, err := client.app.installationID(ctx, "other", "firmware"); err != nil || id != 43 {
+		t.Fatalf("installationID() = %d, %v, want 43 while another lookup is running", id, err)
+	}
+
+	close(release)
+	for range 3 {
+		if id := <-results; id != 42 {
+			t.Errorf("installationID() = %d, want 42", id)
+		}
+	}
+	if got := lookups.Load(); got != 1 {
+		t.Errorf("looked up the installation %d times, want 1", got)
+	}
+}
@@ -4,58 +4,160 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/oauth2"
 )
 
+// CallTimeout bounds every client method, within the deadline of the
+// context it's called with
+const CallTimeout = 30 * time.Second
+
 type Client struct {
 	token  string
 	client *github.Client
-	ctx    context.Context
+	// app is set when authenticating as a GitHub App, see NewAppClient
+	app *appAuth
+	// limits are the rate limits reported by GitHub, see RateLimits
+	limits *rateLimits
 }
 
 type IssueRequest struct {
-	Title  string   `json:"title"`
-	Body   string   `json:"body"`
-	Labels []string `json:"labels,omitempty"`
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	// Milestone is the title or number of an open milestone
+	Milestone string `json:"milestone,omitempty"`
 }
 
 type IssueResponse struct {
 	Number  int    `json:"number"`
 	HTMLURL string `json:"html_url"`
 	ID      int64  `json:"id"`
+	// NodeID identifies the issue in the GraphQL API
+	NodeID string `json:"node_id"`
 }
 
 func NewClient(token string) *Client {
-	ctx := context.Background()
-
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
+	tc := oauth2.NewClient(context.Background(), ts)
+
+	limits := newRateLimits()
+	tc.Transport = &rateLimitTransport{base: tc.Transport, limits: limits}
 
 	return &Client{
 		token:  token,
 		client: github.NewClient(tc),
-		ctx:    ctx,
+		limits: limits,
+	}
+}
+
+// SetAPIURL points the client at the REST API of GitHub Enterprise Server,
+// e.g. https://ghe.example.com/api/v3, or a local stand-in for GitHub. It
+// must be called before the client is used.
+func (c *Client) SetAPIURL(apiURL string) error {
+	baseURL, err := url.Parse(strings.TrimRight(apiURL, "/") + "/")
+	if err != nil {
+		return fmt.Errorf("invalid GitHub API URL: %w", err)
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return fmt.Errorf("invalid GitHub API URL: %s", apiURL)
+	}
+
+	uploadURL := *baseURL
+	if strings.HasSuffix(baseURL.Path, "/api/v3/") {
+		uploadURL.Path = strings.TrimSuffix(baseURL.Path, "v3/") + "uploads/"
+	}
+	c.client.BaseURL, c.client.UploadURL = baseURL, &uploadURL
+	return nil
+}
+
+// clientFor returns the client to call the API with for a repository. With
+// a GitHub App that is the client of the app's installation on the owner.
+func (c *Client) clientFor(ctx context.Context, owner, repo string) (*github.Client, error) {
+	if c.app == nil {
+		return c.client, nil
+	}
+	return c.app.installationClient(ctx, owner, repo)
+}
+
+// Login returns the login the client acts as on GitHub: the owner of the
+// token, or "<slug>[bot]" for a GitHub App
+func (c *Client) Login(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	if c.app != nil {
+		app, resp, err := c.client.Apps.Get(ctx, "")
+		if err != nil {
+			if resp != nil {
+				return "", fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+			}
+			return "", err
+		}
+		return app.GetSlug() + "[bot]", nil
+	}
+
+	user, resp, err := c.client.Users.Get(ctx, "")
+	if err != nil {
+		if resp != nil {
+			return "", fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return "", err
 	}
+	return user.GetLogin(), nil
+}
+
+func (c *Client) CreateIssue(ctx context.Context, owner, repo, title, body string, labels []string) (*IssueResponse, error) {
+	return c.CreateIssueFromRequest(ctx, owner, repo, IssueRequest{Title: title, Body: body, Labels: labels})
 }
 
-func (c *Client) CreateIssue(owner, repo, title, body string, labels []string) (*IssueResponse, error) {
+// CreateIssueFromRequest creates an issue with labels, assignees and a
+// milestone. GitHub silently drops assignees the token can't assign; a
+// milestone that can't be found is logged and skipped.
+func (c *Client) CreateIssueFromRequest(ctx context.Context, owner, repo string, request IssueRequest) (*IssueResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
 	log.Printf("[GitHub API] Creating issue in %s/%s", owner, repo)
-	log.Printf("[GitHub API] Title: %s", title)
-	log.Printf("[GitHub API] Labels: %v", labels)
+	log.Printf("[GitHub API] Title: %s", request.Title)
+	log.Printf("[GitHub API] Labels: %v", request.Labels)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
 
 	req := &github.IssueRequest{
-		Title: github.String(title),
-		Body:  github.String(body),
+		Title: github.String(request.Title),
+		Body:  github.String(request.Body),
 	}
 
 	// go-github requires *string slices, so we adapt if labels exist
-	if len(labels) > 0 {
-		req.Labels = &labels
+	if len(request.Labels) > 0 {
+		req.Labels = &request.Labels
+	}
+	if len(request.Assignees) > 0 {
+		log.Printf("[GitHub API] Assignees: %v", request.Assignees)
+		req.Assignees = &request.Assignees
+	}
+	if request.Milestone != "" {
+		number, err := c.findMilestone(ctx, owner, repo, request.Milestone)
+		if err != nil {
+			log.Printf("[GitHub API] Not setting milestone %q: %v", request.Milestone, err)
+		} else {
+			req.Milestone = github.Int(number)
+		}
 	}
 
-	issue, resp, err := c.client.Issues.Create(c.ctx, owner, repo, req)
+	issue, resp, err := client.Issues.Create(ctx, owner, repo, req)
 	if err != nil {
 		if resp != nil {
 			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
@@ -67,9 +169,269 @@ func (c *Client) CreateIssue(owner, repo, title, body string, labels []string) (
 		Number:  issue.GetNumber(),
 		HTMLURL: issue.GetHTMLURL(),
 		ID:      issue.GetID(),
+		NodeID:  issue.GetNodeID(),
 	}, nil
 }
 
+// findMilestone returns the number of a milestone given its number or the
+// title of an open milestone
+func (c *Client) findMilestone(ctx context.Context, owner, repo, milestone string) (int, error) {
+	if number, err := strconv.Atoi(milestone); err == nil {
+		return number, nil
+	}
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+
+	opts := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		milestones, resp, err := client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			if resp != nil {
+				return 0, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+			}
+			return 0, err
+		}
+		for _, m := range milestones {
+			if strings.EqualFold(m.GetTitle(), milestone) {
+				return m.GetNumber(), nil
+			}
+		}
+		if resp.NextPage == 0 {
+			return 0, fmt.Errorf("no open milestone named %q in %s/%s", milestone, owner, repo)
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// EditIssueBody replaces the body of an issue
+func (c *Client) EditIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Editing body of %s/%s#%d", owner, repo, number)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := client.Issues.Edit(ctx, owner, repo, number, &github.IssueRequest{
+		Body: github.String(body),
+	})
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// IssueSummary is an issue returned by a search
+type IssueSummary struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	HTMLURL  string `json:"html_url"`
+	Comments int    `json:"comments"`
+}
+
+// searchPageSize is the number of search results ranked by matching terms
+const searchPageSize = 30
+
+// SearchOpenIssues returns up to limit open issues in the repo that match
+// any of the search terms, those matching the most terms first
+func (c *Client) SearchOpenIssues(ctx context.Context, owner, repo string, terms []string, limit int) ([]*IssueSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	query := BuildIssueSearchQuery(owner, repo, terms)
+	log.Printf("[GitHub API] Searching issues: %s", query)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result, resp, err := client.Search.Issues(ctx, query, &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: max(limit, searchPageSize)},
+	})
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+
+	// GitHub's relevance order breaks ties between issues matching as many terms
+	issues := make([]*IssueSummary, 0, len(result.Issues))
+	hits := make(map[int]int, len(result.Issues))
+	for _, issue := range result.Issues {
+		hits[issue.GetNumber()] = CountTerms(issue.GetTitle()+"\n"+issue.GetBody(), terms)
+		issues = append(issues, &IssueSummary{
+			Number:   issue.GetNumber(),
+			Title:    issue.GetTitle(),
+			State:    issue.GetState(),
+			HTMLURL:  issue.GetHTMLURL(),
+			Comments: issue.GetComments(),
+		})
+	}
+	sort.SliceStable(issues, func(a, b int) bool {
+		return hits[issues[a].Number] > hits[issues[b].Number]
+	})
+	if len(issues) > limit {
+		issues = issues[:limit]
+	}
+	return issues, nil
+}
+
+// CountTerms returns how many of the search terms appear as words in text
+func CountTerms(text string, terms []string) int {
+	words := make(map[string]bool)
+	for _, word := range searchWords(text) {
+		words[word] = true
+	}
+
+	count := 0
+	for _, term := range terms {
+		if words[term] {
+			count++
+		}
+	}
+	return count
+}
+
+// CreateComment adds a comment to an issue
+func (c *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Commenting on %s/%s#%d", owner, repo, number)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	comment, resp, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{
+		Body: github.String(body),
+	})
+	if err != nil {
+		if resp != nil {
+			return "", fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return "", err
+	}
+
+	return comment.GetHTMLURL(), nil
+}
+
+// CommitFile adds a file to a repository's default branch, returning a link
+// that serves its content to anyone who can read the repository
+func (c *Client) CommitFile(ctx context.Context, owner, repo, path, message string, content []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Committing %s to %s/%s", path, owner, repo)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	file, resp, err := client.Repositories.CreateFile(ctx, owner, repo, path, &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: content,
+	})
+	if err != nil {
+		if resp != nil {
+			return "", fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return "", err
+	}
+
+	return file.Content.GetHTMLURL() + "?raw=true", nil
+}
+
+// BuildIssueSearchQuery builds a search for open issues in a repo that
+// mention any of the terms in their title or body
+func BuildIssueSearchQuery(owner, repo string, terms []string) string {
+	return fmt.Sprintf("repo:%s/%s is:issue is:open in:title,body %s", owner, repo, strings.Join(terms, " OR "))
+}
+
+// searchStopWords are common words that would match almost every issue
+var searchStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "when": true, "not": true,
+	"does": true, "doesn": true, "can": true, "cannot": true, "after": true, "from": true,
+	"this": true, "that": true, "are": true, "was": true, "but": true, "have": true,
+	"has": true, "into": true, "out": true, "there": true, "any": true, "all": true,
+	"bug": true, "feature": true, "request": true, "issue": true, "please": true,
+	"add": true, "app": true, "work": true, "working": true, "works": true,
+}
+
+// SearchTerms picks up to max distinct keywords from the texts, in order,
+// skipping a leading template prefix such as "[Bug]: ", short words and
+// common words
+func SearchTerms(max int, texts ...string) []string {
+	terms := make([]string, 0, max)
+	seen := make(map[string]bool)
+
+	for _, text := range texts {
+		for _, word := range searchWords(stripTitlePrefix(text)) {
+			if len(terms) == max {
+				return terms
+			}
+			if len(word) < 3 || searchStopWords[word] || seen[word] {
+				continue
+			}
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// searchWords splits text into lowercased words
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stripTitlePrefix removes a leading "[...]:" prefix from an issue title
+func stripTitlePrefix(title string) string {
+	trimmed := strings.TrimSpace(title)
+	if !strings.HasPrefix(trimmed, "[") {
+		return title
+	}
+	end := strings.Index(trimmed, "]")
+	if end == -1 {
+		return title
+	}
+	return strings.TrimPrefix(trimmed[end+1:], ":")
+}
+
+// FormatIssueTitle combines an issue template's title prefix (e.g. "[Bug]: ")
+// with the title the user entered. The prefix is not repeated if the user
+// already typed it.
+func FormatIssueTitle(prefix, title string) string {
+	title = strings.TrimSpace(title)
+	if prefix == "" || title == "" {
+		return title
+	}
+	if strings.HasPrefix(strings.ToLower(title), strings.ToLower(strings.TrimSpace(prefix))) {
+		return title
+	}
+	return prefix + title
+}
+
 func FormatIssueBody(username, userID, description string) string {
 	return fmt.Sprintf(`**Reported by:** %s (ID: %s)
 
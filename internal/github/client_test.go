@@ -0,0 +1,286 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// newTestClient returns a Client that sends API requests to a test server
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: client}
+}
+
+func TestSetAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiURL      string
+		wantBase    string
+		wantUpload  string
+		wantGraphQL string
+		wantErr     bool
+	}{
+		{
+			name:        "GitHub Enterprise Server",
+			apiURL:      "https://ghe.example.com/api/v3",
+			wantBase:    "https://ghe.example.com/api/v3/",
+			wantUpload:  "https://ghe.example.com/api/uploads/",
+			wantGraphQL: "https://ghe.example.com/api/graphql",
+		},
+		{
+			name:        "local stand-in",
+			apiURL:      "http://localhost:9000/",
+			wantBase:    "http://localhost:9000/",
+			wantUpload:  "http://localhost:9000/",
+			wantGraphQL: "http://localhost:9000/graphql",
+		},
+		{
+			name:    "not a URL",
+			apiURL:  "ghe.example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("token")
+			err := client.SetAPIURL(tt.apiURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("SetAPIURL(%q) expected error, got nil", tt.apiURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetAPIURL(%q) unexpected error: %v", tt.apiURL, err)
+			}
+
+			if got := client.client.BaseURL.String(); got != tt.wantBase {
+				t.Errorf("BaseURL = %q, want %q", got, tt.wantBase)
+			}
+			if got := client.client.UploadURL.String(); got != tt.wantUpload {
+				t.Errorf("UploadURL = %q, want %q", got, tt.wantUpload)
+			}
+			req, err := client.client.NewRequest(http.MethodPost, graphQLPath(client.client), nil)
+			if err != nil {
+				t.Fatalf("NewRequest() unexpected error: %v", err)
+			}
+			if got := req.URL.String(); got != tt.wantGraphQL {
+				t.Errorf("GraphQL URL = %q, want %q", got, tt.wantGraphQL)
+			}
+		})
+	}
+}
+
+func TestFormatIssueTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		title    string
+		expected string
+	}{
+		{
+			name:     "prefix prepended",
+			prefix:   "[Bug]: ",
+			title:    "App crashes on start",
+			expected: "[Bug]: App crashes on start",
+		},
+		{
+			name:     "no prefix",
+			prefix:   "",
+			title:    "App crashes on start",
+			expected: "App crashes on start",
+		},
+		{
+			name:     "prefix already typed by user",
+			prefix:   "[Bug]: ",
+			title:    "[bug]: App crashes on start",
+			expected: "[bug]: App crashes on start",
+		},
+		{
+			name:     "surrounding whitespace trimmed",
+			prefix:   "[Feature Request]: ",
+			title:    "  Dark mode  ",
+			expected: "[Feature Request]: Dark mode",
+		},
+		{
+			name:     "empty title",
+			prefix:   "[Bug]: ",
+			title:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatIssueTitle(tt.prefix, tt.title)
+			if result != tt.expected {
+				t.Errorf("FormatIssueTitle(%q, %q) = %q, want %q", tt.prefix, tt.title, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		texts    []string
+		expected []string
+	}{
+		{
+			name:     "template prefix and common words skipped",
+			max:      6,
+			texts:    []string{"[Bug]: The app crashes when pairing over Bluetooth"},
+			expected: []string{"crashes", "pairing", "over", "bluetooth"},
+		},
+		{
+			name:     "title terms come first and are not repeated",
+			max:      6,
+			texts:    []string{"GPS position not updating", "The GPS position stays at the last fix after reboot"},
+			expected: []string{"gps", "position", "updating", "stays", "last", "fix"},
+		},
+		{
+			name:     "short words and punctuation dropped",
+			max:      3,
+			texts:    []string{"T-Beam v1.2: OLED is blank!"},
+			expected: []string{"beam", "oled", "blank"},
+		},
+		{
+			name:     "nothing to search for",
+			max:      6,
+			texts:    []string{"", "the and for"},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SearchTerms(tt.max, tt.texts...)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("SearchTerms(%d, %q) = %q, want %q", tt.max, tt.texts, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCountTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "Crash when pairing over Bluetooth", want: 2},
+		{text: "Crashes while pairing", want: 1},
+		{text: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := CountTerms(tt.text, []string{"crash", "pairing"}); got != tt.want {
+			t.Errorf("CountTerms(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSearchOpenIssues(t *testing.T) {
+	var query string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total_count": 2, "items": [
+			{"number": 40, "title": "Pairing fails", "state": "open", "html_url": "https://github.com/meshtastic/firmware/issues/40"},
+			{"number": 12, "title": "Crash when pairing", "state": "open", "html_url": "https://github.com/meshtastic/firmware/issues/12", "comments": 3}
+		]}`))
+	}))
+
+	issues, err := client.SearchOpenIssues(context.Background(), "meshtastic", "firmware", []string{"crash", "pairing"}, 5)
+	if err != nil {
+		t.Fatalf("SearchOpenIssues() unexpected error: %v", err)
+	}
+
+	expectedQuery := "repo:meshtastic/firmware is:issue is:open in:title,body crash OR pairing"
+	if query != expectedQuery {
+		t.Errorf("search query = %q, want %q", query, expectedQuery)
+	}
+	if len(issues) != 2 || issues[0].Number != 12 || issues[0].Comments != 3 || issues[1].Title != "Pairing fails" {
+		t.Errorf("SearchOpenIssues() = %+v, want the issue matching both terms first", issues)
+	}
+}
+
+func TestCommitFile(t *testing.T) {
+	var method, path string
+	var request struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		json.NewDecoder(r.Body).Decode(&request)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"content": {"html_url": "https://github.com/meshtastic/bot-attachments/blob/main/2026/screen.png"}}`))
+	}))
+
+	link, err := client.CommitFile(context.Background(), "meshtastic", "bot-attachments", "2026/screen.png", "Add screen.png", []byte("png"))
+	if err != nil {
+		t.Fatalf("CommitFile() unexpected error: %v", err)
+	}
+	if method != http.MethodPut || path != "/repos/meshtastic/bot-attachments/contents/2026/screen.png" {
+		t.Errorf("CommitFile() sent %s %s, want PUT to the file's contents", method, path)
+	}
+	if request.Message != "Add screen.png" || request.Content != base64.StdEncoding.EncodeToString([]byte("png")) {
+		t.Errorf("CommitFile() sent %+v, want the message and encoded content", request)
+	}
+	if want := "https://github.com/meshtastic/bot-attachments/blob/main/2026/screen.png?raw=true"; link != want {
+		t.Errorf("CommitFile() = %q, want %q", link, want)
+	}
+}
+
+func TestClient_RateLimitsAndCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Reset", "1760000000")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		fmt.Fprint(w, `{"number": 1, "title": "Crash"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	if err := client.SetAPIURL(server.URL); err != nil {
+		t.Fatalf("SetAPIURL() unexpected error: %v", err)
+	}
+	if limits := client.RateLimits(); len(limits) != 0 {
+		t.Errorf("RateLimits() before any request = %+v, want none", limits)
+	}
+
+	if _, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 1); err != nil {
+		t.Fatalf("GetIssue() unexpected error: %v", err)
+	}
+	want := []RateLimit{{Resource: "core", Limit: 5000, Remaining: 4321, Reset: time.Unix(1760000000, 0)}}
+	if limits := client.RateLimits(); !reflect.DeepEqual(limits, want) {
+		t.Errorf("RateLimits() = %+v, want %+v", limits, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.GetIssue(ctx, "meshtastic", "firmware", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetIssue() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
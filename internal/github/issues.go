@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// IssueDetails is an issue or pull request as shown in Discord
+type IssueDetails struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	// State is "open" or "closed", StateReason explains why an issue was closed
+	State         string    `json:"state"`
+	StateReason   string    `json:"state_reason"`
+	HTMLURL       string    `json:"html_url"`
+	Author        string    `json:"author"`
+	Labels        []string  `json:"labels"`
+	Assignees     []string  `json:"assignees"`
+	Milestone     string    `json:"milestone"`
+	Comments      int       `json:"comments"`
+	IsPullRequest bool      `json:"is_pull_request"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// GetIssue returns an issue or pull request
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*IssueDetails, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Getting %s/%s#%d", owner, repo, number)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, resp, err := client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+
+	return convertIssue(issue), nil
+}
+
+// ListLinkedPullRequests returns the pull requests that reference an issue,
+// from the first page of its timeline
+func (c *Client) ListLinkedPullRequests(ctx context.Context, owner, repo string, number int) ([]*IssueSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Listing pull requests linked to %s/%s#%d", owner, repo, number)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	events, resp, err := client.Issues.ListIssueTimeline(ctx, owner, repo, number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+
+	pulls := make([]*IssueSummary, 0)
+	seen := make(map[string]bool)
+	for _, event := range events {
+		if event.GetEvent() != "cross-referenced" || event.Source == nil || event.Source.Issue == nil {
+			continue
+		}
+		source := event.Source.Issue
+		if !source.IsPullRequest() || seen[source.GetHTMLURL()] {
+			continue
+		}
+		seen[source.GetHTMLURL()] = true
+		pulls = append(pulls, &IssueSummary{
+			Number:   source.GetNumber(),
+			Title:    source.GetTitle(),
+			State:    source.GetState(),
+			HTMLURL:  source.GetHTMLURL(),
+			Comments: source.GetComments(),
+		})
+	}
+	return pulls, nil
+}
+
+// FindIssueByMarker returns the newest issue created since a time whose body
+// contains marker, or nil when there is none. It finds issues an earlier
+// attempt created even though the response to it was lost.
+func (c *Client) FindIssueByMarker(ctx context.Context, owner, repo string, since time.Time, marker string) (*IssueResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Looking for %s in %s/%s", marker, owner, repo)
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, resp, err := client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "created",
+		Direction:   "desc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		if issue.IsPullRequest() || issue.GetCreatedAt().Before(since) {
+			continue
+		}
+		if strings.Contains(issue.GetBody(), marker) {
+			return &IssueResponse{
+				Number:  issue.GetNumber(),
+				HTMLURL: issue.GetHTMLURL(),
+				ID:      issue.GetID(),
+				NodeID:  issue.GetNodeID(),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func convertIssue(issue *github.Issue) *IssueDetails {
+	details := &IssueDetails{
+		Number:        issue.GetNumber(),
+		Title:         issue.GetTitle(),
+		Body:          issue.GetBody(),
+		State:         issue.GetState(),
+		StateReason:   issue.GetStateReason(),
+		HTMLURL:       issue.GetHTMLURL(),
+		Author:        issue.GetUser().GetLogin(),
+		Milestone:     issue.GetMilestone().GetTitle(),
+		Comments:      issue.GetComments(),
+		IsPullRequest: issue.IsPullRequest(),
+		CreatedAt:     issue.GetCreatedAt().Time,
+		UpdatedAt:     issue.GetUpdatedAt().Time,
+	}
+	for _, label := range issue.Labels {
+		details.Labels = append(details.Labels, label.GetName())
+	}
+	for _, assignee := range issue.Assignees {
+		details.Assignees = append(details.Assignees, assignee.GetLogin())
+	}
+	return details
+}
@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetIssue(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/meshtastic/firmware/issues/7" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{
+			"number": 7, "title": "Crash", "body": "It crashed", "state": "closed", "state_reason": "not_planned",
+			"html_url": "https://github.com/meshtastic/firmware/issues/7", "user": {"login": "alice"},
+			"labels": [{"name": "bug"}], "assignees": [{"login": "bob"}], "milestone": {"title": "2.5"},
+			"comments": 3, "pull_request": {"url": "https://api.github.com/repos/meshtastic/firmware/pulls/7"}
+		}`)
+	}))
+
+	issue, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 7)
+	if err != nil {
+		t.Fatalf("GetIssue() unexpected error: %v", err)
+	}
+	if issue.Title != "Crash" || issue.StateReason != "not_planned" || issue.Author != "alice" || issue.Milestone != "2.5" {
+		t.Errorf("GetIssue() = %+v", issue)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != "bug" || len(issue.Assignees) != 1 || issue.Assignees[0] != "bob" {
+		t.Errorf("GetIssue() labels = %v, assignees = %v", issue.Labels, issue.Assignees)
+	}
+	if !issue.IsPullRequest {
+		t.Error("GetIssue() IsPullRequest = false, want true")
+	}
+
+	if _, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 8); err == nil {
+		t.Error("GetIssue() of a missing issue returned no error")
+	}
+}
+
+func TestListLinkedPullRequests(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"event": "labeled"},
+			{"event": "cross-referenced", "source": {"issue": {"number": 3, "title": "Another issue", "html_url": "https://github.com/o/r/issues/3"}}},
+			{"event": "cross-referenced", "source": {"issue": {"number": 9, "title": "Fix crash", "state": "open", "html_url": "https://github.com/o/r/pull/9", "pull_request": {}}}},
+			{"event": "cross-referenced", "source": {"issue": {"number": 9, "title": "Fix crash", "state": "open", "html_url": "https://github.com/o/r/pull/9", "pull_request": {}}}}
+		]`)
+	}))
+
+	pulls, err := client.ListLinkedPullRequests(context.Background(), "o", "r", 1)
+	if err != nil {
+		t.Fatalf("ListLinkedPullRequests() unexpected error: %v", err)
+	}
+	if len(pulls) != 1 || pulls[0].Number != 9 || pulls[0].Title != "Fix crash" {
+		t.Errorf("ListLinkedPullRequests() = %+v, want only pull request #9", pulls)
+	}
+}
+
+func TestCreateIssueFromRequest(t *testing.T) {
+	var created map[string]interface{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/milestones":
+			fmt.Fprint(w, `[{"number": 3, "title": "2.5"}, {"number": 4, "title": "2.6"}]`)
+		case "/repos/o/r/issues":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("decoding issue request: %v", err)
+			}
+			fmt.Fprint(w, `{"number": 12, "html_url": "https://github.com/o/r/issues/12"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	tests := []struct {
+		name      string
+		milestone string
+		expected  interface{}
+	}{
+		{name: "milestone title", milestone: "2.6", expected: float64(4)},
+		{name: "milestone number", milestone: "7", expected: float64(7)},
+		{name: "unknown milestone is skipped", milestone: "3.0", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			issue, err := client.CreateIssueFromRequest(context.Background(), "o", "r", IssueRequest{
+				Title:     "Crash",
+				Body:      "It crashed",
+				Labels:    []string{"bug"},
+				Assignees: []string{"maintainer"},
+				Milestone: tt.milestone,
+			})
+			if err != nil {
+				t.Fatalf("CreateIssueFromRequest() unexpected error: %v", err)
+			}
+			if issue.Number != 12 {
+				t.Errorf("CreateIssueFromRequest().Number = %d, want 12", issue.Number)
+			}
+			if created["milestone"] != tt.expected {
+				t.Errorf("milestone = %v, want %v", created["milestone"], tt.expected)
+			}
+			if assignees, _ := created["assignees"].([]interface{}); len(assignees) != 1 || assignees[0] != "maintainer" {
+				t.Errorf("assignees = %v, want [maintainer]", created["assignees"])
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Project identifies a Projects (v2) board by its owner's login and number,
+// as in https://github.com/orgs/<owner>/projects/<number>
+type Project struct {
+	Owner  string
+	Number int
+}
+
+// projectField is a field of a project board, with the options of single
+// select fields such as Status
+type projectField struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Options  []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"options"`
+}
+
+const projectQuery = `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        fields(first: 100) {
+          nodes {
+            ... on ProjectV2FieldCommon { id name dataType }
+            ... on ProjectV2SingleSelectField { options { id name } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const addProjectItemMutation = `mutation($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) {
+    item { id }
+  }
+}`
+
+const updateProjectFieldMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: $value}) {
+    projectV2Item { id }
+  }
+}`
+
+// AddIssueToProject adds an issue to a project board and sets its fields,
+// e.g. Status to Triage. Fields are matched by name, and single select
+// options by their name. Every field is attempted even if one fails.
+func (c *Client) AddIssueToProject(ctx context.Context, project Project, issueNodeID string, fields map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	log.Printf("[GitHub API] Adding issue %s to project %s/%d", issueNodeID, project.Owner, project.Number)
+
+	// Projects belong to an account, so a GitHub App uses its installation there
+	client, err := c.clientFor(ctx, project.Owner, "")
+	if err != nil {
+		return err
+	}
+
+	var board struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID     string `json:"id"`
+				Fields struct {
+					Nodes []projectField `json:"nodes"`
+				} `json:"fields"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	err = c.graphQL(ctx, client, projectQuery, map[string]interface{}{"owner": project.Owner, "number": project.Number}, &board)
+	if err != nil {
+		return fmt.Errorf("failed to look up project: %w", err)
+	}
+	if board.RepositoryOwner == nil || board.RepositoryOwner.ProjectV2 == nil {
+		return fmt.Errorf("project %s/%d not found", project.Owner, project.Number)
+	}
+	projectID := board.RepositoryOwner.ProjectV2.ID
+
+	var added struct {
+		AddProjectV2ItemById struct {
+			Item struct {
+				ID string `json:"id"`
+			} `json:"item"`
+		} `json:"addProjectV2ItemById"`
+	}
+	err = c.graphQL(ctx, client, addProjectItemMutation, map[string]interface{}{"project": projectID, "content": issueNodeID}, &added)
+	if err != nil {
+		return fmt.Errorf("failed to add issue to project: %w", err)
+	}
+	itemID := added.AddProjectV2ItemById.Item.ID
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		value := fields[name]
+		field := findProjectField(board.RepositoryOwner.ProjectV2.Fields.Nodes, name)
+		if field == nil {
+			errs = append(errs, fmt.Errorf("project has no field %q", name))
+			continue
+		}
+		fieldValue, err := projectFieldValue(field, value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		err = c.graphQL(ctx, client, updateProjectFieldMutation, map[string]interface{}{
+			"project": projectID,
+			"item":    itemID,
+			"field":   field.ID,
+			"value":   fieldValue,
+		}, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to set %s: %w", field.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func findProjectField(fields []projectField, name string) *projectField {
+	for index := range fields {
+		if strings.EqualFold(fields[index].Name, name) {
+			return &fields[index]
+		}
+	}
+	return nil
+}
+
+// projectFieldValue builds the ProjectV2FieldValue input setting a field to value
+func projectFieldValue(field *projectField, value string) (map[string]interface{}, error) {
+	switch field.DataType {
+	case "SINGLE_SELECT":
+		for _, option := range field.Options {
+			if strings.EqualFold(option.Name, value) {
+				return map[string]interface{}{"singleSelectOptionId": option.ID}, nil
+			}
+		}
+		return nil, fmt.Errorf("%s has no option %q", field.Name, value)
+	case "TEXT":
+		return map[string]interface{}{"text": value}, nil
+	case "NUMBER":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s needs a number, got %q", field.Name, value)
+		}
+		return map[string]interface{}{"number": number}, nil
+	case "DATE":
+		return map[string]interface{}{"date": value}, nil
+	}
+	return nil, fmt.Errorf("%s fields of type %s can't be set", field.Name, field.DataType)
+}
+
+// graphQLPath returns the GraphQL endpoint relative to the REST API, which
+// GitHub Enterprise Server serves from /api/graphql beside /api/v3
+func graphQLPath(client *github.Client) string {
+	if strings.HasSuffix(client.BaseURL.Path, "/api/v3/") {
+		return "../graphql"
+	}
+	return "graphql"
+}
+
+// graphQL runs a query against the GraphQL API, decoding its data into result
+func (c *Client) graphQL(ctx context.Context, client *github.Client, query string, variables map[string]interface{}, result interface{}) error {
+	req, err := client.NewRequest(http.MethodPost, graphQLPath(client), map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp, err := client.Do(ctx, req, &response)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+		}
+		return err
+	}
+	if len(response.Errors) > 0 {
+		return fmt.Errorf("github GraphQL API returned an error: %s", response.Errors[0].Message)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Data, result)
+}
@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testProjectResponse = `{"data": {"repositoryOwner": {"projectV2": {"id": "PVT_1", "fields": {"nodes": [
+	{"id": "F_title", "name": "Title", "dataType": "TITLE"},
+	{"id": "F_status", "name": "Status", "dataType": "SINGLE_SELECT", "options": [{"id": "O_triage", "name": "Triage"}, {"id": "O_done", "name": "Done"}]},
+	{"id": "F_source", "name": "Source", "dataType": "TEXT"}
+]}}}}}`
+
+type graphQLCall struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func TestAddIssueToProject(t *testing.T) {
+	tests := []struct {
+		name          string
+		fields        map[string]string
+		expectedSets  map[string]string
+		expectedError string
+	}{
+		{
+			name:         "sets fields",
+			fields:       map[string]string{"status": "triage", "Source": "Discord"},
+			expectedSets: map[string]string{"F_status": `{"singleSelectOptionId":"O_triage"}`, "F_source": `{"text":"Discord"}`},
+		},
+		{
+			name:          "unknown fields and options are reported",
+			fields:        map[string]string{"Priority": "High", "Status": "Blocked", "Source": "Discord"},
+			expectedSets:  map[string]string{"F_source": `{"text":"Discord"}`},
+			expectedError: "project has no field \"Priority\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added := false
+			sets := make(map[string]string)
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var call graphQLCall
+				if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+					t.Errorf("decoding GraphQL request: %v", err)
+				}
+				switch {
+				case strings.Contains(call.Query, "repositoryOwner"):
+					fmt.Fprint(w, testProjectResponse)
+				case strings.Contains(call.Query, "addProjectV2ItemById"):
+					added = call.Variables["project"] == "PVT_1" && call.Variables["content"] == "I_7"
+					fmt.Fprint(w, `{"data": {"addProjectV2ItemById": {"item": {"id": "PVTI_1"}}}}`)
+				case strings.Contains(call.Query, "updateProjectV2ItemFieldValue"):
+					value, _ := json.Marshal(call.Variables["value"])
+					sets[call.Variables["field"].(string)] = string(value)
+					fmt.Fprint(w, `{"data": {}}`)
+				}
+			}))
+
+			err := client.AddIssueToProject(context.Background(), Project{Owner: "meshtastic", Number: 5}, "I_7", tt.fields)
+			if tt.expectedError == "" && err != nil {
+				t.Errorf("AddIssueToProject() unexpected error: %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+				t.Errorf("AddIssueToProject() error = %v, want %q", err, tt.expectedError)
+			}
+			if !added {
+				t.Error("AddIssueToProject() didn't add the issue to the project")
+			}
+			if fmt.Sprint(sets) != fmt.Sprint(tt.expectedSets) {
+				t.Errorf("field values = %v, want %v", sets, tt.expectedSets)
+			}
+		})
+	}
+}
+
+func TestAddIssueToProject_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected string
+	}{
+		{name: "project not found", response: `{"data": {"repositoryOwner": {}}}`, expected: "project meshtastic/5 not found"},
+		{name: "GraphQL error", response: `{"data": null, "errors": [{"message": "Resource not accessible by integration"}]}`, expected: "Resource not accessible by integration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tt.response)
+			}))
+
+			err := client.AddIssueToProject(context.Background(), Project{Owner: "meshtastic", Number: 5}, "I_7", nil)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("AddIssueToProject() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
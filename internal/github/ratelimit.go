@@ -0,0 +1,86 @@
+package github
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is GitHub's rate limit for a kind of request, as reported with
+// the latest response
+type RateLimit struct {
+	// Resource is the kind of request, e.g. "core", "search" or "graphql"
+	Resource  string
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// rateLimits are the latest rate limits by resource
+type rateLimits struct {
+	mu     sync.Mutex
+	limits map[string]RateLimit
+}
+
+func newRateLimits() *rateLimits {
+	return &rateLimits{limits: make(map[string]RateLimit)}
+}
+
+// observe records the rate limit headers of a response, if it has any
+func (r *rateLimits) observe(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	reset, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = "core"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits[resource] = RateLimit{
+		Resource:  resource,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Unix(reset, 0),
+	}
+}
+
+// RateLimits returns the rate limits GitHub reported with its latest
+// responses, by resource name
+func (c *Client) RateLimits() []RateLimit {
+	if c.limits == nil {
+		return nil
+	}
+
+	c.limits.mu.Lock()
+	defer c.limits.mu.Unlock()
+
+	limits := make([]RateLimit, 0, len(c.limits.limits))
+	for _, limit := range c.limits.limits {
+		limits = append(limits, limit)
+	}
+	sort.Slice(limits, func(a, b int) bool {
+		return limits[a].Resource < limits[b].Resource
+	})
+	return limits
+}
+
+// rateLimitTransport records the rate limit headers of every response
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	limits *rateLimits
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.limits.observe(resp.Header)
+	}
+	return resp, err
+}
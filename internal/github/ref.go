@@ -0,0 +1,77 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultWebHost is the host of issue URLs on github.com
+const DefaultWebHost = "github.com"
+
+// IssueRef identifies an issue or pull request. Owner and Repo are empty
+// when the reference didn't name them, e.g. "#123".
+type IssueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+func (r IssueRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// ParseIssueRef parses "123", "#123", "repo#123", "owner/repo#123" or an
+// issue or pull request URL on webHost, e.g. "github.com"
+func ParseIssueRef(text, webHost string) (IssueRef, error) {
+	text = strings.TrimSpace(text)
+
+	if strings.Contains(text, "://") {
+		return parseIssueURL(text, webHost)
+	}
+
+	repoPart, numberPart, found := strings.Cut(text, "#")
+	if !found {
+		numberPart, repoPart = text, ""
+	}
+
+	number, err := strconv.Atoi(numberPart)
+	if err != nil || number <= 0 {
+		return IssueRef{}, fmt.Errorf("%q is not an issue number", numberPart)
+	}
+
+	ref := IssueRef{Number: number}
+	if repoPart == "" {
+		return ref, nil
+	}
+
+	owner, repo, hasOwner := strings.Cut(repoPart, "/")
+	if !hasOwner {
+		owner, repo = "", repoPart
+	}
+	if repo == "" || strings.Contains(repo, "/") || (hasOwner && owner == "") {
+		return IssueRef{}, fmt.Errorf("%q is not a repository", repoPart)
+	}
+	ref.Owner, ref.Repo = owner, repo
+	return ref, nil
+}
+
+// parseIssueURL parses https://<webHost>/<owner>/<repo>/(issues|pull)/<number>
+func parseIssueURL(text, webHost string) (IssueRef, error) {
+	parsed, err := url.Parse(text)
+	if err != nil || !strings.EqualFold(parsed.Host, webHost) {
+		return IssueRef{}, fmt.Errorf("%q is not a %s issue URL", text, webHost)
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 4 || (parts[2] != "issues" && parts[2] != "pull") {
+		return IssueRef{}, fmt.Errorf("%q is not a %s issue URL", text, webHost)
+	}
+
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return IssueRef{}, fmt.Errorf("%q is not a %s issue URL", text, webHost)
+	}
+	return IssueRef{Owner: parts[0], Repo: parts[1], Number: number}, nil
+}
@@ -0,0 +1,43 @@
+package github
+
+import "testing"
+
+func TestParseIssueRef(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected IssueRef
+		wantErr  bool
+	}{
+		{name: "number", text: "123", expected: IssueRef{Number: 123}},
+		{name: "hash number", text: " #42 ", expected: IssueRef{Number: 42}},
+		{name: "repo", text: "firmware#7", expected: IssueRef{Repo: "firmware", Number: 7}},
+		{name: "owner and repo", text: "meshtastic/web#9", expected: IssueRef{Owner: "meshtastic", Repo: "web", Number: 9}},
+		{name: "issue URL", text: "https://github.com/meshtastic/firmware/issues/5000", expected: IssueRef{Owner: "meshtastic", Repo: "firmware", Number: 5000}},
+		{name: "pull request URL", text: "https://github.com/meshtastic/web/pull/12/files", expected: IssueRef{Owner: "meshtastic", Repo: "web", Number: 12}},
+		{name: "URL on another host", text: "https://example.com/meshtastic/web/issues/1", wantErr: true},
+		{name: "not an issue URL", text: "https://github.com/meshtastic/web/tree/main", wantErr: true},
+		{name: "not a number", text: "firmware#abc", wantErr: true},
+		{name: "zero", text: "#0", wantErr: true},
+		{name: "empty owner", text: "/web#1", wantErr: true},
+		{name: "nested repository", text: "a/b/c#1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseIssueRef(tt.text, DefaultWebHost)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseIssueRef(%q) = %+v, want an error", tt.text, ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIssueRef(%q) unexpected error: %v", tt.text, err)
+			}
+			if ref != tt.expected {
+				t.Errorf("ParseIssueRef(%q) = %+v, want %+v", tt.text, ref, tt.expected)
+			}
+		})
+	}
+}
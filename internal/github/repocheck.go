@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// newLabelColor is the color of labels created by CheckRepository, GitHub's
+// default grey
+const newLabelColor = "ededed"
+
+// RepositoryCheck is the outcome of checking that issues can be created in a
+// repository the way the bot is configured to
+type RepositoryCheck struct {
+	Owner string
+	Repo  string
+	// Problems are why issues can't be created as configured
+	Problems []string
+	// CreatedLabels were missing and have been created
+	CreatedLabels []string
+}
+
+// OK reports whether the repository passed the check
+func (r *RepositoryCheck) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *RepositoryCheck) String() string {
+	var b strings.Builder
+	if r.OK() {
+		fmt.Fprintf(&b, "%s/%s: OK", r.Owner, r.Repo)
+	} else {
+		fmt.Fprintf(&b, "%s/%s: FAILED", r.Owner, r.Repo)
+	}
+	if len(r.CreatedLabels) > 0 {
+		fmt.Fprintf(&b, " (created labels %s)", strings.Join(r.CreatedLabels, ", "))
+	}
+	for _, problem := range r.Problems {
+		b.WriteString("\n  - " + problem)
+	}
+	return b.String()
+}
+
+// CheckRepository checks that the client can create issues in a repository
+// and apply labels to them, creating any of the labels that are missing
+func (c *Client) CheckRepository(ctx context.Context, owner, repo string, labels []string) *RepositoryCheck {
+	ctx, cancel := context.WithTimeout(ctx, CallTimeout)
+	defer cancel()
+
+	check := &RepositoryCheck{Owner: owner, Repo: repo}
+	problem := func(format string, args ...interface{}) *RepositoryCheck {
+		check.Problems = append(check.Problems, fmt.Sprintf(format, args...))
+		return check
+	}
+
+	client, err := c.clientFor(ctx, owner, repo)
+	if err != nil {
+		return problem("no access: %v", err)
+	}
+
+	repository, resp, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		if resp != nil {
+			return problem("repository can't be read, github API returned %d: %v", resp.StatusCode, err)
+		}
+		return problem("repository can't be read: %v", err)
+	}
+	if !repository.GetHasIssues() {
+		return problem("issues are disabled")
+	}
+	if repository.GetArchived() {
+		return problem("repository is archived")
+	}
+	// Permissions aren't reported to GitHub App installations
+	if permissions := repository.GetPermissions(); permissions != nil && !canTriage(permissions) {
+		problem("labels and assignees need triage access or more, but the token only has read access")
+	}
+
+	existing, err := c.listLabels(ctx, client, owner, repo)
+	if err != nil {
+		return problem("labels can't be listed: %v", err)
+	}
+	for _, label := range labels {
+		if existing[strings.ToLower(label)] {
+			continue
+		}
+		log.Printf("[GitHub API] Creating label %q in %s/%s", label, owner, repo)
+		_, resp, err := client.Issues.CreateLabel(ctx, owner, repo, &github.Label{
+			Name:  github.String(label),
+			Color: github.String(newLabelColor),
+		})
+		if err != nil {
+			if resp != nil {
+				err = fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+			}
+			problem("label %q is missing and couldn't be created: %v", label, err)
+			continue
+		}
+		check.CreatedLabels = append(check.CreatedLabels, label)
+	}
+
+	return check
+}
+
+// canTriage reports whether repository permissions allow labeling issues
+func canTriage(permissions map[string]bool) bool {
+	return permissions["triage"] || permissions["push"] || permissions["maintain"] || permissions["admin"]
+}
+
+// listLabels returns the lowercased names of a repository's labels
+func (c *Client) listLabels(ctx context.Context, client *github.Client, owner, repo string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := client.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			if resp != nil {
+				return nil, fmt.Errorf("github API returned %d: %w", resp.StatusCode, err)
+			}
+			return nil, err
+		}
+		for _, label := range labels {
+			names[strings.ToLower(label.GetName())] = true
+		}
+		if resp.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckRepository(t *testing.T) {
+	tests := []struct {
+		name         string
+		repository   string
+		createStatus int
+		wantOK       bool
+		wantCreated  []string
+		wantProblem  string
+	}{
+		{
+			name:         "creates missing labels",
+			repository:   `{"has_issues": true, "permissions": {"pull": true, "triage": true}}`,
+			createStatus: http.StatusCreated,
+			wantOK:       true,
+			wantCreated:  []string{"from-discord"},
+		},
+		{
+			name:         "missing label can't be created",
+			repository:   `{"has_issues": true}`,
+			createStatus: http.StatusForbidden,
+			wantProblem:  `label "from-discord" is missing`,
+		},
+		{
+			name:        "read-only token",
+			repository:  `{"has_issues": true, "permissions": {"pull": true}}`,
+			wantProblem: "triage access",
+		},
+		{
+			name:        "issues disabled",
+			repository:  `{"has_issues": false}`,
+			wantProblem: "issues are disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created []string
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/repos/meshtastic/firmware":
+					fmt.Fprint(w, tt.repository)
+				case r.URL.Path == "/repos/meshtastic/firmware/labels" && r.Method == http.MethodGet:
+					fmt.Fprint(w, `[{"name": "Bug"}]`)
+				case r.URL.Path == "/repos/meshtastic/firmware/labels" && r.Method == http.MethodPost:
+					var label struct {
+						Name string `json:"name"`
+					}
+					json.NewDecoder(r.Body).Decode(&label)
+					w.WriteHeader(tt.createStatus)
+					if tt.createStatus == http.StatusCreated {
+						created = append(created, label.Name)
+					}
+					fmt.Fprint(w, `{}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+
+			check := client.CheckRepository(context.Background(), "meshtastic", "firmware", []string{"bug", "from-discord"})
+			if check.OK() != tt.wantOK {
+				t.Errorf("CheckRepository().OK() = %v, want %v: %s", check.OK(), tt.wantOK, check)
+			}
+			if !reflect.DeepEqual(check.CreatedLabels, tt.wantCreated) || !reflect.DeepEqual(created, tt.wantCreated) {
+				t.Errorf("CheckRepository() created %v (reported %v), want %v", created, check.CreatedLabels, tt.wantCreated)
+			}
+			if tt.wantProblem != "" && !strings.Contains(check.String(), tt.wantProblem) {
+				t.Errorf("CheckRepository() = %q, want a problem containing %q", check, tt.wantProblem)
+			}
+		})
+	}
+}
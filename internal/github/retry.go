@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Retryable reports whether a failed API call may succeed if it's retried:
+// network errors, timeouts, server errors and rate limits may. Anything
+// else, such as a request GitHub rejected, a missing App installation or a
+// response that can't be decoded, won't. The delay is how long GitHub asked
+// to wait before retrying, or zero when it didn't say.
+func Retryable(err error) (time.Duration, bool) {
+	var secondary *github.AbuseRateLimitError
+	if errors.As(err, &secondary) {
+		return secondary.GetRetryAfter(), true
+	}
+	var primary *github.RateLimitError
+	if errors.As(err, &primary) {
+		return max(time.Until(primary.Rate.Reset.Time), 0), true
+	}
+
+	var response *github.ErrorResponse
+	if errors.As(err, &response) {
+		if response.Response == nil {
+			return 0, false
+		}
+		status := response.Response.StatusCode
+		if status != http.StatusTooManyRequests && status < http.StatusInternalServerError {
+			return 0, false
+		}
+		return RetryAfter(response.Response.Header), true
+	}
+
+	return 0, isNetworkError(err)
+}
+
+// IsNotFound reports whether an API call failed because what it asked for
+// doesn't exist or was deleted
+func IsNotFound(err error) bool {
+	var response *github.ErrorResponse
+	if !errors.As(err, &response) || response.Response == nil {
+		return false
+	}
+	status := response.Response.StatusCode
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// isNetworkError reports whether a request failed to get a response in
+// time or over the network. *url.Error is a net.Error even for a malformed
+// request, so it's judged by the error it wraps.
+func isNetworkError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as a date
+func RetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
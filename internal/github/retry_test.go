@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		headers       map[string]string
+		body          string
+		wantRetryable bool
+		wantDelay     time.Duration
+	}{
+		{name: "server error", status: http.StatusBadGateway, wantRetryable: true},
+		{name: "server error with Retry-After", status: http.StatusServiceUnavailable, headers: map[string]string{"Retry-After": "120"}, wantRetryable: true, wantDelay: 2 * time.Minute},
+		{name: "too many requests", status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "7"}, wantRetryable: true, wantDelay: 7 * time.Second},
+		{
+			name:          "secondary rate limit",
+			status:        http.StatusForbidden,
+			headers:       map[string]string{"Retry-After": "60"},
+			body:          `{"message": "You have exceeded a secondary rate limit.", "documentation_url": "https://docs.github.com/rest/overview/resources-in-the-rest-api#secondary-rate-limits"}`,
+			wantRetryable: true,
+			wantDelay:     time.Minute,
+		},
+		{name: "validation failed", status: http.StatusUnprocessableEntity, body: `{"message": "Validation Failed"}`},
+		{name: "not found", status: http.StatusNotFound, body: `{"message": "Not Found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.headers {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+
+			_, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 1)
+			if err == nil {
+				t.Fatal("GetIssue() expected error, got nil")
+			}
+			delay, retryable := Retryable(err)
+			if retryable != tt.wantRetryable || delay != tt.wantDelay {
+				t.Errorf("Retryable() = %v, %v, want %v, %v", delay, retryable, tt.wantDelay, tt.wantRetryable)
+			}
+		})
+	}
+
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   bool
+	}{
+		{name: "not found", status: http.StatusNotFound, want: true},
+		{name: "deleted", status: http.StatusGone, want: true},
+		{name: "server error", status: http.StatusBadGateway},
+		{name: "forbidden", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message": "error"}`))
+			}))
+
+			_, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 1)
+			if got := IsNotFound(err); got != tt.want {
+				t.Errorf("IsNotFound(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+
+	if IsNotFound(context.DeadlineExceeded) {
+		t.Error("IsNotFound() of a timeout = true, want false")
+	}
+}
+
+func TestRetryable_WithoutResponse(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+	}{
+		{name: "connection refused", err: &url.Error{Op: "Post", URL: "https://api.github.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, wantRetryable: true},
+		{name: "timeout", err: fmt.Errorf("create issue: %w", context.DeadlineExceeded), wantRetryable: true},
+		{name: "canceled", err: &url.Error{Op: "Post", URL: "https://api.github.com", Err: context.Canceled}, wantRetryable: true},
+		{name: "app not installed", err: errors.New("GitHub App is not installed on meshtastic")},
+		{name: "bad URL", err: &url.Error{Op: "Post", URL: "ftp://example.com", Err: errors.New("unsupported protocol scheme")}},
+		{name: "undecodable response", err: &json.SyntaxError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, retryable := Retryable(tt.err); retryable != tt.wantRetryable {
+				t.Errorf("Retryable(%v) = %v, want %v", tt.err, retryable, tt.wantRetryable)
+			}
+		})
+	}
+}
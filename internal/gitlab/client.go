@@ -0,0 +1,311 @@
+// Package gitlab files issues in GitLab projects through the REST API v4
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// Client implements tracker.IssueTracker for a GitLab instance. Owners are
+// the project's namespace, which may be a nested group.
+type Client struct {
+	api *tracker.API
+	// webURL is where projects are browsed, e.g. https://gitlab.com
+	webURL string
+}
+
+var _ tracker.IssueTracker = (*Client)(nil)
+
+// NewClient returns a client for the GitLab instance at webURL, e.g.
+// https://gitlab.com, authenticated with a personal, group or project
+// access token
+func NewClient(webURL, token string) (*Client, error) {
+	webURL = strings.TrimRight(webURL, "/")
+	api, err := tracker.NewAPI("GitLab", webURL+"/api/v4", http.Header{"Private-Token": {token}})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: api, webURL: webURL}, nil
+}
+
+// issue is an issue as returned by the API
+type issue struct {
+	ID          int64    `json:"id"`
+	IID         int      `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	WebURL      string   `json:"web_url"`
+	Labels      []string `json:"labels"`
+	Notes       int      `json:"user_notes_count"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+	Assignees []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// state maps GitLab's "opened" to the "open" used by the other trackers
+func (i *issue) state() string {
+	if i.State == "opened" {
+		return "open"
+	}
+	return i.State
+}
+
+// projectPath is the URL-encoded path identifying a project in the API
+func projectPath(owner, repo string) string {
+	return "projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+// CreateIssueFromRequest creates an issue. GitLab creates labels that don't
+// exist yet; assignees and milestones that can't be found are logged and
+// skipped.
+func (c *Client) CreateIssueFromRequest(ctx context.Context, owner, repo string, request tracker.IssueRequest) (*tracker.IssueResponse, error) {
+	c.api.Logf("Creating issue in %s/%s", owner, repo)
+	c.api.Logf("Title: %s", request.Title)
+	c.api.Logf("Labels: %v", request.Labels)
+
+	body := map[string]interface{}{
+		"title":       request.Title,
+		"description": request.Body,
+	}
+	if len(request.Labels) > 0 {
+		body["labels"] = strings.Join(request.Labels, ",")
+	}
+
+	assigneeIDs := make([]int64, 0, len(request.Assignees))
+	for _, username := range request.Assignees {
+		id, err := c.findUser(ctx, username)
+		if err != nil {
+			c.api.Logf("Not assigning %q: %v", username, err)
+			continue
+		}
+		assigneeIDs = append(assigneeIDs, id)
+	}
+	if len(assigneeIDs) > 0 {
+		c.api.Logf("Assignees: %v", request.Assignees)
+		body["assignee_ids"] = assigneeIDs
+	}
+
+	if request.Milestone != "" {
+		id, err := c.findMilestone(ctx, owner, repo, request.Milestone)
+		if err != nil {
+			c.api.Logf("Not setting milestone %q: %v", request.Milestone, err)
+		} else {
+			body["milestone_id"] = id
+		}
+	}
+
+	var created issue
+	if err := c.api.Do(ctx, http.MethodPost, projectPath(owner, repo)+"/issues", nil, body, &created); err != nil {
+		return nil, err
+	}
+	return &tracker.IssueResponse{Number: created.IID, HTMLURL: created.WebURL, ID: created.ID}, nil
+}
+
+// findUser returns the ID of the user with a username
+func (c *Client) findUser(ctx context.Context, username string) (int64, error) {
+	var users []struct {
+		ID int64 `json:"id"`
+	}
+	err := c.api.Do(ctx, http.MethodGet, "users", url.Values{"username": {username}}, nil, &users)
+	if err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("no user %q", username)
+	}
+	return users[0].ID, nil
+}
+
+// findMilestone returns the ID of an active milestone, given its title or
+// its number in the project
+func (c *Client) findMilestone(ctx context.Context, owner, repo, milestone string) (int64, error) {
+	query := url.Values{"state": {"active"}, "title": {milestone}}
+	if _, err := strconv.Atoi(milestone); err == nil {
+		query = url.Values{"state": {"active"}, "iids[]": {milestone}}
+	}
+
+	var milestones []struct {
+		ID int64 `json:"id"`
+	}
+	err := c.api.Do(ctx, http.MethodGet, projectPath(owner, repo)+"/milestones", query, nil, &milestones)
+	if err != nil {
+		return 0, err
+	}
+	if len(milestones) == 0 {
+		return 0, fmt.Errorf("no active milestone %q", milestone)
+	}
+	return milestones[0].ID, nil
+}
+
+// EditIssueBody replaces the description of an issue
+func (c *Client) EditIssueBody(ctx context.Context, owner, repo string, number int, body string) error {
+	c.api.Logf("Editing %s/%s#%d", owner, repo, number)
+
+	path := fmt.Sprintf("%s/issues/%d", projectPath(owner, repo), number)
+	return c.api.Do(ctx, http.MethodPut, path, nil, map[string]string{"description": body}, nil)
+}
+
+// SearchOpenIssues returns up to limit open issues with any of the terms in
+// their title or description. GitLab matches every word of a search, so
+// each term is searched for on its own.
+func (c *Client) SearchOpenIssues(ctx context.Context, owner, repo string, terms []string, limit int) ([]*tracker.IssueSummary, error) {
+	c.api.Logf("Searching issues in %s/%s: %s", owner, repo, strings.Join(terms, " OR "))
+
+	return tracker.SearchEach(terms, limit, func(term string) ([]*tracker.IssueSummary, error) {
+		var found []issue
+		err := c.api.Do(ctx, http.MethodGet, projectPath(owner, repo)+"/issues", url.Values{
+			"state":    {"opened"},
+			"search":   {term},
+			"in":       {"title,description"},
+			"per_page": {strconv.Itoa(limit)},
+		}, nil, &found)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries := make([]*tracker.IssueSummary, 0, len(found))
+		for index := range found {
+			summaries = append(summaries, &tracker.IssueSummary{
+				Number:   found[index].IID,
+				Title:    found[index].Title,
+				State:    found[index].state(),
+				HTMLURL:  found[index].WebURL,
+				Comments: found[index].Notes,
+			})
+		}
+		return summaries, nil
+	})
+}
+
+// CreateComment adds a note to an issue and returns its URL
+func (c *Client) CreateComment(ctx context.Context, owner, repo string, number int, body string) (string, error) {
+	c.api.Logf("Commenting on %s/%s#%d", owner, repo, number)
+
+	var note struct {
+		ID int64 `json:"id"`
+	}
+	path := fmt.Sprintf("%s/issues/%d/notes", projectPath(owner, repo), number)
+	if err := c.api.Do(ctx, http.MethodPost, path, nil, map[string]string{"body": body}, &note); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s/-/issues/%d#note_%d", c.webURL, owner, repo, number, note.ID), nil
+}
+
+// FindIssueByMarker returns the newest issue created since a time whose
+// description contains marker, or nil when there is none
+func (c *Client) FindIssueByMarker(ctx context.Context, owner, repo string, since time.Time, marker string) (*tracker.IssueResponse, error) {
+	c.api.Logf("Looking for %s in %s/%s", marker, owner, repo)
+
+	var found []issue
+	err := c.api.Do(ctx, http.MethodGet, projectPath(owner, repo)+"/issues", url.Values{
+		"created_after": {since.UTC().Format(time.RFC3339)},
+		"order_by":      {"created_at"},
+		"sort":          {"desc"},
+		"per_page":      {"100"},
+	}, nil, &found)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range found {
+		if strings.Contains(found[index].Description, marker) {
+			return &tracker.IssueResponse{Number: found[index].IID, HTMLURL: found[index].WebURL, ID: found[index].ID}, nil
+		}
+	}
+	return nil, nil
+}
+
+// reporterAccess is the access level needed to set labels, assignees and
+// milestones on new issues, which GitLab silently drops otherwise
+const reporterAccess = 20
+
+// CheckRepository checks that the project has issues enabled and that the
+// token may label them. GitLab creates missing labels along with the issue,
+// so labels aren't checked.
+func (c *Client) CheckRepository(ctx context.Context, owner, repo string, labels []string) *tracker.RepositoryCheck {
+	check := &tracker.RepositoryCheck{Owner: owner, Repo: repo}
+	problem := func(format string, args ...interface{}) *tracker.RepositoryCheck {
+		check.Problems = append(check.Problems, fmt.Sprintf(format, args...))
+		return check
+	}
+
+	type access struct {
+		AccessLevel int `json:"access_level"`
+	}
+	var project struct {
+		IssuesEnabled bool `json:"issues_enabled"`
+		Archived      bool `json:"archived"`
+		Permissions   struct {
+			ProjectAccess *access `json:"project_access"`
+			GroupAccess   *access `json:"group_access"`
+		} `json:"permissions"`
+	}
+	if err := c.api.Do(ctx, http.MethodGet, projectPath(owner, repo), nil, nil, &project); err != nil {
+		return problem("project can't be read: %v", err)
+	}
+	if !project.IssuesEnabled {
+		return problem("issues are disabled")
+	}
+	if project.Archived {
+		return problem("project is archived")
+	}
+
+	// Neither is reported to administrators, who have every permission
+	level := 0
+	for _, granted := range []*access{project.Permissions.ProjectAccess, project.Permissions.GroupAccess} {
+		if granted != nil {
+			level = max(level, granted.AccessLevel)
+		}
+	}
+	if level > 0 && level < reporterAccess && len(labels) > 0 {
+		problem("labels need the Reporter role or more, but the token only has access level %d", level)
+	}
+	return check
+}
+
+// GetIssue returns an issue
+func (c *Client) GetIssue(ctx context.Context, owner, repo string, number int) (*tracker.IssueDetails, error) {
+	c.api.Logf("Getting %s/%s#%d", owner, repo, number)
+
+	var found issue
+	path := fmt.Sprintf("%s/issues/%d", projectPath(owner, repo), number)
+	if err := c.api.Do(ctx, http.MethodGet, path, nil, nil, &found); err != nil {
+		return nil, err
+	}
+
+	details := &tracker.IssueDetails{
+		Number:    found.IID,
+		Title:     found.Title,
+		Body:      found.Description,
+		State:     found.state(),
+		HTMLURL:   found.WebURL,
+		Author:    found.Author.Username,
+		Labels:    found.Labels,
+		Comments:  found.Notes,
+		CreatedAt: found.CreatedAt,
+		UpdatedAt: found.UpdatedAt,
+	}
+	for _, assignee := range found.Assignees {
+		details.Assignees = append(details.Assignees, assignee.Username)
+	}
+	if found.Milestone != nil {
+		details.Milestone = found.Milestone.Title
+	}
+	return details, nil
+}
@@ -0,0 +1,177 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/tracker"
+)
+
+// newTestClient returns a client for a fake GitLab instance
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	return client
+}
+
+func TestClient_CreateIssueFromRequest(t *testing.T) {
+	var created map[string]interface{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Private-Token"); got != "token" {
+			t.Errorf("Private-Token = %q, want token", got)
+		}
+
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /api/v4/users":
+			if r.URL.Query().Get("username") == "alice" {
+				w.Write([]byte(`[{"id": 7}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "GET /api/v4/projects/group%2Fsub%2Frepo/milestones":
+			if got := r.URL.Query().Get("title"); got != "2.6" {
+				t.Errorf("milestone title = %q, want 2.6", got)
+			}
+			w.Write([]byte(`[{"id": 3}]`))
+		case "POST /api/v4/projects/group%2Fsub%2Frepo/issues":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1001, "iid": 12, "web_url": "https://gitlab.example/group/sub/repo/-/issues/12"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+			http.NotFound(w, r)
+		}
+	})
+
+	issue, err := client.CreateIssueFromRequest(context.Background(), "group/sub", "repo", tracker.IssueRequest{
+		Title:     "Crash",
+		Body:      "It crashed",
+		Labels:    []string{"bug", "from-discord"},
+		Assignees: []string{"alice", "nobody"},
+		Milestone: "2.6",
+	})
+	if err != nil {
+		t.Fatalf("CreateIssueFromRequest() unexpected error: %v", err)
+	}
+	if issue.Number != 12 || issue.ID != 1001 || issue.HTMLURL != "https://gitlab.example/group/sub/repo/-/issues/12" {
+		t.Errorf("CreateIssueFromRequest() = %+v", issue)
+	}
+
+	want := map[string]interface{}{
+		"title":        "Crash",
+		"description":  "It crashed",
+		"labels":       "bug,from-discord",
+		"assignee_ids": []interface{}{float64(7)},
+		"milestone_id": float64(3),
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("created issue = %v, want %v", created, want)
+	}
+}
+
+func TestClient_GetIssue(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/meshtastic%2Ffirmware/issues/5" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{
+			"iid": 5, "title": "Crash", "description": "Body", "state": "opened",
+			"web_url": "https://gitlab.example/meshtastic/firmware/-/issues/5",
+			"labels": ["bug"], "user_notes_count": 2,
+			"author": {"username": "bob"}, "assignees": [{"username": "alice"}],
+			"milestone": {"title": "2.6"}
+		}`))
+	})
+
+	issue, err := client.GetIssue(context.Background(), "meshtastic", "firmware", 5)
+	if err != nil {
+		t.Fatalf("GetIssue() unexpected error: %v", err)
+	}
+	if issue.State != "open" || issue.Author != "bob" || issue.Comments != 2 || issue.Milestone != "2.6" {
+		t.Errorf("GetIssue() = %+v", issue)
+	}
+	if !reflect.DeepEqual(issue.Assignees, []string{"alice"}) || !reflect.DeepEqual(issue.Labels, []string{"bug"}) {
+		t.Errorf("GetIssue() assignees %v and labels %v", issue.Assignees, issue.Labels)
+	}
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/v4/projects/meshtastic%2Ffirmware/issues/5/notes" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 99}`))
+	})
+
+	url, err := client.CreateComment(context.Background(), "meshtastic", "firmware", 5, "Me too")
+	if err != nil {
+		t.Fatalf("CreateComment() unexpected error: %v", err)
+	}
+	if want := client.webURL + "/meshtastic/firmware/-/issues/5#note_99"; url != want {
+		t.Errorf("CreateComment() = %q, want %q", url, want)
+	}
+}
+
+func TestClient_SearchOpenIssues(t *testing.T) {
+	results := map[string]string{
+		"bluetooth": `[{"iid": 1, "title": "Bluetooth drops", "state": "opened"}, {"iid": 2, "title": "Bluetooth pairing", "state": "opened"}]`,
+		"pairing":   `[{"iid": 2, "title": "Bluetooth pairing", "state": "opened"}]`,
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("state"); got != "opened" {
+			t.Errorf("state = %q, want opened", got)
+		}
+		w.Write([]byte(results[r.URL.Query().Get("search")]))
+	})
+
+	issues, err := client.SearchOpenIssues(context.Background(), "meshtastic", "firmware", []string{"bluetooth", "pairing"}, 5)
+	if err != nil {
+		t.Fatalf("SearchOpenIssues() unexpected error: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Number != 2 || issues[1].Number != 1 || issues[0].State != "open" {
+		t.Errorf("SearchOpenIssues() = %+v, want #2 then #1", issues)
+	}
+}
+
+func TestClient_CheckRepository(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		wantOK  bool
+	}{
+		{name: "developer", project: `{"issues_enabled": true, "permissions": {"project_access": {"access_level": 30}}}`, wantOK: true},
+		{name: "group reporter", project: `{"issues_enabled": true, "permissions": {"group_access": {"access_level": 20}}}`, wantOK: true},
+		{name: "administrator", project: `{"issues_enabled": true, "permissions": {}}`, wantOK: true},
+		{name: "guest", project: `{"issues_enabled": true, "permissions": {"project_access": {"access_level": 10}}}`},
+		{name: "issues disabled", project: `{"issues_enabled": false}`},
+		{name: "archived", project: `{"issues_enabled": true, "archived": true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != "/api/v4/projects/meshtastic%2Ffirmware" {
+					t.Errorf("unexpected path %s", r.URL.EscapedPath())
+				}
+				w.Write([]byte(tt.project))
+			})
+
+			check := client.CheckRepository(context.Background(), "meshtastic", "firmware", []string{"bug"})
+			if check.OK() != tt.wantOK {
+				t.Errorf("CheckRepository() = %s, want OK %v", check, tt.wantOK)
+			}
+		})
+	}
+}
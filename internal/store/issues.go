@@ -0,0 +1,118 @@
+package store
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IssueRecord links an issue created from Discord to the user who reported it
+type IssueRecord struct {
+	Owner     string `json:"owner"`
+	Repo      string `json:"repo"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	HTMLURL   string `json:"html_url"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	// ThreadID is the companion thread of the issue, if one was opened
+	ThreadID string `json:"thread_id,omitempty"`
+	// Tracker and TrackerURL name the GitLab or Gitea instance the issue
+	// is on, both are empty for GitHub
+	Tracker    string    `json:"tracker,omitempty"`
+	TrackerURL string    `json:"tracker_url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Key identifies the issue, see IssueKey
+func (r IssueRecord) Key() string {
+	return IssueKey(r.Tracker, r.TrackerURL, r.Owner, r.Repo, r.Number)
+}
+
+// IssueKey identifies an issue as "owner/repo#number" on GitHub, and as
+// "tracker:url/owner/repo#number" on GitLab and Gitea, so issues with the
+// same number on different trackers don't collide. Owner and repo are
+// case-insensitive on every tracker, so they are lowercased.
+func IssueKey(tracker, trackerURL, owner, repo string, number int) string {
+	key := fmt.Sprintf("%s/%s#%d", strings.ToLower(owner), strings.ToLower(repo), number)
+	if tracker == "" {
+		return key
+	}
+	return fmt.Sprintf("%s:%s/%s", tracker, strings.TrimRight(trackerURL, "/"), key)
+}
+
+// IssueStore keeps a record of every issue created from Discord, optionally
+// written to a JSON file on every change
+type IssueStore struct {
+	mu     sync.RWMutex
+	path   string
+	issues map[string]IssueRecord
+}
+
+// NewIssueStore returns an IssueStore that keeps the records in memory only
+func NewIssueStore() *IssueStore {
+	return &IssueStore{issues: make(map[string]IssueRecord)}
+}
+
+// NewFileIssueStore loads the issue records from path and writes them back
+// on every change
+func NewFileIssueStore(path string) (*IssueStore, error) {
+	issueStore := NewIssueStore()
+	issueStore.path = path
+
+	var loaded map[string]IssueRecord
+	if err := ReadJSON(path, &loaded); err != nil {
+		return nil, err
+	}
+	// Records of GitLab and Gitea issues used to be saved under the same
+	// keys as GitHub issues
+	for _, record := range loaded {
+		issueStore.issues[record.Key()] = record
+	}
+
+	log.Printf("Loaded %d issue records from %s", len(issueStore.issues), path)
+	return issueStore, nil
+}
+
+// Record saves an issue record, replacing any earlier record of the issue
+func (s *IssueStore) Record(record IssueRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.issues[record.Key()] = record
+	if s.path != "" {
+		if err := WriteJSON(s.path, s.issues); err != nil {
+			log.Printf("Failed to save issue records: %v", err)
+		}
+	}
+}
+
+// Get returns the record of a GitHub issue, if it was created from Discord.
+// Issues on other trackers are never returned.
+func (s *IssueStore) Get(owner, repo string, number int) (IssueRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.issues[IssueKey("", "", owner, repo, number)]
+	return record, ok
+}
+
+// ListByUser returns the issues a user reported, newest first
+func (s *IssueStore) ListByUser(userID string) []IssueRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]IssueRecord, 0)
+	for _, record := range s.issues {
+		if record.UserID == userID {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(a, b int) bool {
+		return records[a].CreatedAt.After(records[b].CreatedAt)
+	})
+	return records
+}
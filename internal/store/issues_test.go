@@ -0,0 +1,87 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIssueStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.json")
+
+	issueStore, err := NewFileIssueStore(path)
+	if err != nil {
+		t.Fatalf("NewFileIssueStore() unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "firmware", Number: 1, UserID: "alice", CreatedAt: now.Add(-time.Hour)})
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 2, UserID: "alice", CreatedAt: now})
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 3, UserID: "bob", CreatedAt: now})
+
+	reloaded, err := NewFileIssueStore(path)
+	if err != nil {
+		t.Fatalf("NewFileIssueStore() reload unexpected error: %v", err)
+	}
+
+	record, ok := reloaded.Get("Meshtastic", "Firmware", 1)
+	if !ok || record.UserID != "alice" {
+		t.Errorf("Get() = %+v, %v, want alice's issue regardless of case", record, ok)
+	}
+	if _, ok := reloaded.Get("meshtastic", "firmware", 2); ok {
+		t.Error("Get() returned a record for an unknown issue")
+	}
+
+	records := reloaded.ListByUser("alice")
+	if len(records) != 2 || records[0].Number != 2 || records[1].Number != 1 {
+		t.Errorf("ListByUser() = %+v, want alice's two issues newest first", records)
+	}
+}
+
+func TestIssueStore_InMemory(t *testing.T) {
+	issueStore := NewIssueStore()
+
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 5, UserID: "alice"})
+	if _, ok := issueStore.Get("meshtastic", "web", 5); !ok {
+		t.Error("Get() after Record() returned no record")
+	}
+}
+
+func TestIssueStore_SeparatesTrackers(t *testing.T) {
+	issueStore := NewIssueStore()
+
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 5, UserID: "alice"})
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 5, UserID: "bob", Tracker: "gitlab", TrackerURL: "https://gitlab.example"})
+	issueStore.Record(IssueRecord{Owner: "meshtastic", Repo: "web", Number: 5, UserID: "carol", Tracker: "gitea", TrackerURL: "https://codeberg.org"})
+
+	if record, ok := issueStore.Get("meshtastic", "web", 5); !ok || record.UserID != "alice" {
+		t.Errorf("Get() = %+v, %v, want the GitHub issue", record, ok)
+	}
+	if records := issueStore.ListByUser("bob"); len(records) != 1 || records[0].Tracker != "gitlab" {
+		t.Errorf("ListByUser() = %+v, want the GitLab issue kept alongside the GitHub one", records)
+	}
+	if records := issueStore.ListByUser("carol"); len(records) != 1 {
+		t.Errorf("ListByUser() = %+v, want the Gitea issue kept alongside the GitHub one", records)
+	}
+}
+
+func TestIssueStore_RekeysOldRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "issues.json")
+	old := map[string]IssueRecord{
+		"meshtastic/web#5": {Owner: "meshtastic", Repo: "web", Number: 5, UserID: "bob", Tracker: "gitlab", TrackerURL: "https://gitlab.example"},
+	}
+	if err := WriteJSON(path, old); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+
+	issueStore, err := NewFileIssueStore(path)
+	if err != nil {
+		t.Fatalf("NewFileIssueStore() unexpected error: %v", err)
+	}
+	if _, ok := issueStore.Get("meshtastic", "web", 5); ok {
+		t.Error("Get() returned a GitLab issue saved under its old key")
+	}
+	if records := issueStore.ListByUser("bob"); len(records) != 1 {
+		t.Errorf("ListByUser() = %+v, want the GitLab issue", records)
+	}
+}
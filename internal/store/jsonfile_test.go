@@ -0,0 +1,56 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJSONFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "data.json")
+
+	want := map[string]int{"a": 1, "b": 2}
+	if err := WriteJSON(path, want); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+
+	var got map[string]int
+	if err := ReadJSON(path, &got); err != nil {
+		t.Fatalf("ReadJSON() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadJSON() = %v, want %v", got, want)
+	}
+
+	// No temp files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("WriteJSON() left %d files in the directory, want 1", len(entries))
+	}
+}
+
+func TestReadJSON_MissingFile(t *testing.T) {
+	got := map[string]int{"keep": 1}
+	if err := ReadJSON(filepath.Join(t.TempDir(), "missing.json"), &got); err != nil {
+		t.Fatalf("ReadJSON() missing file unexpected error: %v", err)
+	}
+	if got["keep"] != 1 {
+		t.Errorf("ReadJSON() missing file modified the value: %v", got)
+	}
+}
+
+func TestReadJSON_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	var got map[string]int
+	if err := ReadJSON(path, &got); err == nil {
+		t.Error("ReadJSON() invalid file expected error, got nil")
+	}
+}
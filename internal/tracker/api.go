@@ -0,0 +1,148 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+// maxErrorBody is how much of an error response is kept for its message
+const maxErrorBody = 4096
+
+// API calls the JSON REST API of a GitLab or Gitea instance
+type API struct {
+	// Name names the tracker in logs and errors, e.g. "GitLab"
+	Name string
+	// BaseURL is the root of the API, e.g. https://gitlab.com/api/v4/
+	BaseURL *url.URL
+	// Header is sent with every request, e.g. to authenticate
+	Header http.Header
+	Client *http.Client
+}
+
+// NewAPI returns an API rooted at baseURL
+func NewAPI(name, baseURL string, header http.Header) (*API, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/") + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s API URL: %w", name, err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid %s API URL: %s", name, baseURL)
+	}
+	return &API{Name: name, BaseURL: parsed, Header: header, Client: http.DefaultClient}, nil
+}
+
+// Do sends body as JSON to a path relative to the API root and decodes the
+// response into result, unless either is nil. Responses other than 2xx are
+// returned as an *HTTPError. Every call is bounded by github.CallTimeout.
+func (a *API) Do(ctx context.Context, method, path string, query url.Values, body, result interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, github.CallTimeout)
+	defer cancel()
+
+	endpoint, err := a.BaseURL.Parse(path)
+	if err != nil {
+		return err
+	}
+	if query != nil {
+		endpoint.RawQuery = query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), reader)
+	if err != nil {
+		return err
+	}
+	for key, values := range a.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newHTTPError(a.Name, resp)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode %s API response: %w", a.Name, err)
+	}
+	return nil
+}
+
+// HTTPError is a response other than 2xx from a tracker's API
+type HTTPError struct {
+	Name       string
+	StatusCode int
+	Message    string
+	// RetryAfter is how long the tracker asked to wait, or zero
+	RetryAfter time.Duration
+}
+
+func newHTTPError(name string, resp *http.Response) *HTTPError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	message := strings.TrimSpace(string(data))
+
+	// GitLab and Gitea both explain errors with a "message" field
+	var decoded struct {
+		Message interface{} `json:"message"`
+	}
+	if json.Unmarshal(data, &decoded) == nil && decoded.Message != nil {
+		message = fmt.Sprint(decoded.Message)
+	}
+
+	return &HTTPError{
+		Name:       name,
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RetryAfter: github.RetryAfter(resp.Header),
+	}
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s API returned %d: %s", strings.ToLower(e.Name), e.StatusCode, e.Message)
+}
+
+// Retryable reports whether a failed call to any tracker may succeed if
+// it's retried, and how long the tracker asked to wait. See github.Retryable.
+func Retryable(err error) (time.Duration, bool) {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return github.Retryable(err)
+	}
+	if httpErr.StatusCode != http.StatusTooManyRequests && httpErr.StatusCode < http.StatusInternalServerError {
+		return 0, false
+	}
+	return httpErr.RetryAfter, true
+}
+
+// Logf logs a call to the tracker's API, like the GitHub client does
+func (a *API) Logf(format string, args ...interface{}) {
+	log.Printf("["+a.Name+" API] "+format, args...)
+}
@@ -0,0 +1,70 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantRetryable bool
+		wantDelay     time.Duration
+	}{
+		{name: "rate limited", err: &HTTPError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}, wantRetryable: true, wantDelay: time.Minute},
+		{name: "server error", err: fmt.Errorf("create: %w", &HTTPError{StatusCode: http.StatusBadGateway}), wantRetryable: true},
+		{name: "not found", err: &HTTPError{StatusCode: http.StatusNotFound}},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, wantRetryable: true},
+		{name: "no client", err: errors.New("no client for tracker gitea at https://gitea.example")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, retryable := Retryable(tt.err)
+			if retryable != tt.wantRetryable || delay != tt.wantDelay {
+				t.Errorf("Retryable() = %v, %v, want %v, %v", delay, retryable, tt.wantDelay, tt.wantRetryable)
+			}
+		})
+	}
+}
+
+func TestSearchEach(t *testing.T) {
+	results := map[string][]*IssueSummary{
+		"radio":   {{Number: 1}, {Number: 2}},
+		"lora":    {{Number: 2}, {Number: 3}},
+		"antenna": {{Number: 2}},
+	}
+	search := func(term string) ([]*IssueSummary, error) { return results[term], nil }
+
+	tests := []struct {
+		name  string
+		terms []string
+		limit int
+		want  []int
+	}{
+		{name: "most matches first", terms: []string{"radio", "lora", "antenna"}, limit: 5, want: []int{2, 1, 3}},
+		{name: "limited", terms: []string{"radio", "lora"}, limit: 2, want: []int{2, 1}},
+		{name: "no terms", limit: 5, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := SearchEach(tt.terms, tt.limit, search)
+			if err != nil {
+				t.Fatalf("SearchEach() unexpected error: %v", err)
+			}
+			got := make([]int, 0, len(issues))
+			for _, issue := range issues {
+				got = append(got, issue.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("SearchEach() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package tracker is the interface the bot files and looks up issues
+// through, implemented for GitHub, GitLab and Gitea/Forgejo
+package tracker
+
+import (
+	"context"
+	"time"
+
+	github "github.com/meshtastic/meshtastic-bot/internal/github"
+)
+
+// The GitHub client's types are shared by every tracker. Numbers are the
+// issue numbers shown to users: GitLab's iid and Gitea's index.
+type (
+	IssueRequest  = github.IssueRequest
+	IssueResponse = github.IssueResponse
+	IssueSummary  = github.IssueSummary
+	IssueDetails  = github.IssueDetails
+	// RepositoryCheck is the outcome of a tracker's startup check
+	RepositoryCheck = github.RepositoryCheck
+)
+
+// IssueTracker files issues and looks them up in a repository, called a
+// project on GitLab
+type IssueTracker interface {
+	// CreateIssueFromRequest creates an issue with labels, assignees and a
+	// milestone. Assignees and milestones that can't be found are skipped.
+	CreateIssueFromRequest(ctx context.Context, owner, repo string, request IssueRequest) (*IssueResponse, error)
+	// EditIssueBody replaces the body of an issue
+	EditIssueBody(ctx context.Context, owner, repo string, number int, body string) error
+	// SearchOpenIssues returns up to limit open issues matching any of the
+	// search terms, those matching the most terms first
+	SearchOpenIssues(ctx context.Context, owner, repo string, terms []string, limit int) ([]*IssueSummary, error)
+	// CreateComment adds a comment to an issue and returns its URL
+	CreateComment(ctx context.Context, owner, repo string, number int, body string) (string, error)
+	// GetIssue returns an issue
+	GetIssue(ctx context.Context, owner, repo string, number int) (*IssueDetails, error)
+	// FindIssueByMarker returns the newest issue created since a time whose
+	// body contains marker, or nil when there is none
+	FindIssueByMarker(ctx context.Context, owner, repo string, since time.Time, marker string) (*IssueResponse, error)
+	// CheckRepository checks that issues can be created in a repository
+	// with the labels, creating labels that are missing where needed
+	CheckRepository(ctx context.Context, owner, repo string, labels []string) *RepositoryCheck
+}
+
+var _ IssueTracker = (*github.Client)(nil)
+
+// SearchEach runs a search for every term on trackers that can't search for
+// any of several terms at once. Issues matching more terms rank first, then
+// issues found by earlier terms.
+func SearchEach(terms []string, limit int, search func(term string) ([]*IssueSummary, error)) ([]*IssueSummary, error) {
+	var found []*IssueSummary
+	hits := make(map[int]int)
+	for _, term := range terms {
+		issues, err := search(term)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if hits[issue.Number] == 0 {
+				found = append(found, issue)
+			}
+			hits[issue.Number]++
+		}
+	}
+
+	ranked := make([]*IssueSummary, 0, len(found))
+	for count := len(terms); count > 0 && len(ranked) < limit; count-- {
+		for _, issue := range found {
+			if hits[issue.Number] == count && len(ranked) < limit {
+				ranked = append(ranked, issue)
+			}
+		}
+	}
+	return ranked, nil
+}
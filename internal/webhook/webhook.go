@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+
+	"github.com/google/go-github/v57/github"
+)
+
+const (
+	// maxCommentExcerpt is how much of a comment is quoted in a notification
+	maxCommentExcerpt = 300
+	// deliveryRetention is how long delivery IDs are remembered, so
+	// deliveries GitHub or a maintainer sends again are ignored
+	deliveryRetention = 24 * time.Hour
+	// maxPayloadSize is the largest payload GitHub sends
+	maxPayloadSize = 25 << 20
+	// setupWindow is how long after creating an issue the bot may still be
+	// labeling it, editing it and commenting the report's attachments
+	setupWindow = time.Minute
+)
+
+// Notifier tells the reporter of an issue about activity on it
+type Notifier interface {
+	NotifyReporter(record store.IssueRecord, message string) error
+}
+
+// maintainerAssociations are the author associations of comments reporters
+// are notified about
+var maintainerAssociations = map[string]bool{
+	"OWNER":        true,
+	"MEMBER":       true,
+	"COLLABORATOR": true,
+}
+
+// Handler receives GitHub issues and issue_comment webhooks, verified with
+// the shared secret, and notifies reporters of issues created from Discord
+type Handler struct {
+	secret   []byte
+	issues   *store.IssueStore
+	notifier Notifier
+	// botLogin is who the bot acts as on GitHub, see botChange
+	botLogin string
+
+	mu sync.Mutex
+	// deliveries are when recent X-GitHub-Delivery IDs were received
+	deliveries map[string]time.Time
+}
+
+// NewHandler returns a handler for webhooks signed with secret. botLogin is
+// the login the bot acts as on GitHub; when it's empty, the author of the
+// issue is assumed to be the bot.
+func NewHandler(secret string, issues *store.IssueStore, notifier Notifier, botLogin string) *Handler {
+	return &Handler{
+		secret:     []byte(secret),
+		issues:     issues,
+		notifier:   notifier,
+		botLogin:   botLogin,
+		deliveries: make(map[string]time.Time),
+	}
+}
+
+// firstDelivery records a delivery ID and reports whether it's new.
+// Deliveries without an ID are always handled.
+func (h *Handler) firstDelivery(id string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for seen, received := range h.deliveries {
+		if now.Sub(received) > deliveryRetention {
+			delete(h.deliveries, seen)
+		}
+	}
+	if id == "" {
+		return true
+	}
+	if _, ok := h.deliveries[id]; ok {
+		return false
+	}
+	h.deliveries[id] = now
+	return true
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// ValidatePayload checks the X-Hub-Signature-256 HMAC against the secret
+	r.Body = http.MaxBytesReader(w, r.Body, maxPayloadSize)
+	payload, err := github.ValidatePayload(r, h.secret)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		log.Printf("Rejected webhook delivery %s: payload is over %d bytes", github.DeliveryID(r), tooLarge.Limit)
+		http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		log.Printf("Rejected webhook delivery %s: %v", github.DeliveryID(r), err)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !h.firstDelivery(github.DeliveryID(r), time.Now()) {
+		log.Printf("Ignoring repeated webhook delivery %s", github.DeliveryID(r))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		log.Printf("Ignoring webhook delivery %s: %v", github.DeliveryID(r), err)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var repo *github.Repository
+	var issue *github.Issue
+	var message string
+	switch event := event.(type) {
+	case *github.IssuesEvent:
+		repo, issue = event.GetRepo(), event.GetIssue()
+		if !h.botChange(event.GetSender(), issue, issue.GetUpdatedAt().Time) {
+			message = issuesEventMessage(event)
+		}
+	case *github.IssueCommentEvent:
+		repo, issue = event.GetRepo(), event.GetIssue()
+		if !h.botChange(event.GetComment().GetUser(), issue, event.GetComment().GetCreatedAt().Time) {
+			message = issueCommentEventMessage(event)
+		}
+	}
+
+	if message != "" {
+		h.notify(repo, issue, message)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// notify sends the message to the reporter if the GitHub issue was created
+// from Discord
+func (h *Handler) notify(repo *github.Repository, issue *github.Issue, message string) {
+	record, ok := h.issues.Get(repo.GetOwner().GetLogin(), repo.GetName(), issue.GetNumber())
+	if !ok {
+		return
+	}
+
+	if err := h.notifier.NotifyReporter(record, message); err != nil {
+		log.Printf("Failed to notify reporter of %s: %v", record.Key(), err)
+	}
+}
+
+// botChange reports whether a change at a time was made by the bot rather
+// than a maintainer: by a GitHub App or other bot account, or with the bot's
+// login while it sets up an issue it created. A personal access token's
+// owner is also a maintainer, so their later changes aren't the bot's.
+func (h *Handler) botChange(sender *github.User, issue *github.Issue, at time.Time) bool {
+	if sender.GetType() == "Bot" || strings.HasSuffix(sender.GetLogin(), "[bot]") {
+		return true
+	}
+	botLogin := h.botLogin
+	if botLogin == "" {
+		botLogin = issue.GetUser().GetLogin()
+	}
+	return strings.EqualFold(sender.GetLogin(), botLogin) && at.Sub(issue.GetCreatedAt().Time) < setupWindow
+}
+
+// issuesEventMessage describes a closed, reopened or relabeled issue
+func issuesEventMessage(event *github.IssuesEvent) string {
+	issue := event.GetIssue()
+	switch event.GetAction() {
+	case "closed":
+		reason := "completed"
+		if issue.GetStateReason() == "not_planned" {
+			reason = "not planned"
+		}
+		return fmt.Sprintf("🔒 %s closed your issue %s as %s.\n%s",
+			event.GetSender().GetLogin(), issueReference(issue), reason, issue.GetHTMLURL())
+	case "reopened":
+		return fmt.Sprintf("🔓 %s reopened your issue %s.\n%s",
+			event.GetSender().GetLogin(), issueReference(issue), issue.GetHTMLURL())
+	case "labeled":
+		return fmt.Sprintf("🏷️ %s labeled your issue %s as `%s`.\n%s",
+			event.GetSender().GetLogin(), issueReference(issue), event.GetLabel().GetName(), issue.GetHTMLURL())
+	case "unlabeled":
+		return fmt.Sprintf("🏷️ %s removed the `%s` label from your issue %s.\n%s",
+			event.GetSender().GetLogin(), event.GetLabel().GetName(), issueReference(issue), issue.GetHTMLURL())
+	}
+	return ""
+}
+
+// issueCommentEventMessage describes a new comment from a maintainer
+func issueCommentEventMessage(event *github.IssueCommentEvent) string {
+	comment := event.GetComment()
+	if event.GetAction() != "created" || !maintainerAssociations[comment.GetAuthorAssociation()] {
+		return ""
+	}
+
+	return fmt.Sprintf("💬 %s commented on your issue %s:\n%s\n%s",
+		comment.GetUser().GetLogin(), issueReference(event.GetIssue()), quote(comment.GetBody()), comment.GetHTMLURL())
+}
+
+func issueReference(issue *github.Issue) string {
+	return fmt.Sprintf("**#%d %s**", issue.GetNumber(), issue.GetTitle())
+}
+
+// quote quotes the start of a comment for Discord
+func quote(body string) string {
+	body = strings.TrimSpace(body)
+	if runes := []rune(body); len(runes) > maxCommentExcerpt {
+		body = string(runes[:maxCommentExcerpt-3]) + "..."
+	}
+
+	lines := strings.Split(body, "\n")
+	for index, line := range lines {
+		lines[index] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
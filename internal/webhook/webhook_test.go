@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/meshtastic/meshtastic-bot/internal/store"
+)
+
+const testSecret = "webhook-secret"
+
+type recordingNotifier struct {
+	messages []string
+	records  []store.IssueRecord
+}
+
+func (n *recordingNotifier) NotifyReporter(record store.IssueRecord, message string) error {
+	n.records = append(n.records, record)
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func newTestHandler(t *testing.T) (*Handler, *recordingNotifier) {
+	t.Helper()
+
+	issueStore := store.NewIssueStore()
+	issueStore.Record(store.IssueRecord{Owner: "meshtastic", Repo: "firmware", Number: 7, UserID: "alice"})
+
+	notifier := &recordingNotifier{}
+	return NewHandler(testSecret, issueStore, notifier, "bot"), notifier
+}
+
+func signedRequest(event, body, secret string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+
+	req := httptest.NewRequest(http.MethodPost, "/github/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+const repoJSON = `"repository": {"name": "firmware", "owner": {"login": "meshtastic"}}`
+
+func TestHandler_RejectsBadSignature(t *testing.T) {
+	handler, notifier := newTestHandler(t)
+
+	body := `{"action": "closed", "issue": {"number": 7}, ` + repoJSON + `}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest("issues", body, "wrong-secret"))
+
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if len(notifier.messages) != 0 {
+		t.Errorf("notified %d times for a forged delivery", len(notifier.messages))
+	}
+}
+
+func TestHandler_Events(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    string
+		body     string
+		expected string
+	}{
+		{
+			name:     "issue closed",
+			event:    "issues",
+			body:     `{"action": "closed", "sender": {"login": "maintainer"}, "issue": {"number": 7, "title": "Crash", "state_reason": "completed", "user": {"login": "bot"}, "html_url": "https://github.com/meshtastic/firmware/issues/7"}, ` + repoJSON + `}`,
+			expected: "🔒 maintainer closed your issue **#7 Crash** as completed.\nhttps://github.com/meshtastic/firmware/issues/7",
+		},
+		{
+			name:     "issue labeled",
+			event:    "issues",
+			body:     `{"action": "labeled", "sender": {"login": "maintainer"}, "label": {"name": "triaged"}, "issue": {"number": 7, "title": "Crash", "user": {"login": "bot"}}, ` + repoJSON + `}`,
+			expected: "🏷️ maintainer labeled your issue **#7 Crash** as `triaged`.\n",
+		},
+		{
+			name:     "labels added by the bot are ignored",
+			event:    "issues",
+			body:     `{"action": "labeled", "sender": {"login": "bot"}, "label": {"name": "bug"}, "issue": {"number": 7, "user": {"login": "bot"}}, ` + repoJSON + `}`,
+			expected: "",
+		},
+		{
+			name:     "token owner changes after setup are reported",
+			event:    "issues",
+			body:     `{"action": "closed", "sender": {"login": "bot"}, "issue": {"number": 7, "title": "Crash", "user": {"login": "bot"}, "created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-02T10:00:00Z", "html_url": "https://github.com/meshtastic/firmware/issues/7"}, ` + repoJSON + `}`,
+			expected: "🔒 bot closed your issue **#7 Crash** as completed.\nhttps://github.com/meshtastic/firmware/issues/7",
+		},
+		{
+			name:     "app changes are ignored",
+			event:    "issues",
+			body:     `{"action": "labeled", "sender": {"login": "meshtastic-bot[bot]", "type": "Bot"}, "label": {"name": "bug"}, "issue": {"number": 7, "user": {"login": "meshtastic-bot[bot]"}, "created_at": "2026-01-01T10:00:00Z", "updated_at": "2026-01-02T10:00:00Z"}, ` + repoJSON + `}`,
+			expected: "",
+		},
+		{
+			name:     "attachments comment from the bot is ignored",
+			event:    "issue_comment",
+			body:     `{"action": "created", "comment": {"body": "Attachments", "author_association": "MEMBER", "user": {"login": "bot"}, "created_at": "2026-01-01T10:00:05Z"}, "issue": {"number": 7, "user": {"login": "bot"}, "created_at": "2026-01-01T10:00:00Z"}, ` + repoJSON + `}`,
+			expected: "",
+		},
+		{
+			name:     "maintainer comment",
+			event:    "issue_comment",
+			body:     `{"action": "created", "comment": {"body": "Fixed in 2.5", "author_association": "MEMBER", "user": {"login": "maintainer"}, "html_url": "https://github.com/c/1"}, "issue": {"number": 7, "title": "Crash", "user": {"login": "bot"}}, ` + repoJSON + `}`,
+			expected: "💬 maintainer commented on your issue **#7 Crash**:\n> Fixed in 2.5\nhttps://github.com/c/1",
+		},
+		{
+			name:     "comments from other users are ignored",
+			event:    "issue_comment",
+			body:     `{"action": "created", "comment": {"body": "+1", "author_association": "NONE", "user": {"login": "someone"}}, "issue": {"number": 7, "user": {"login": "bot"}}, ` + repoJSON + `}`,
+			expected: "",
+		},
+		{
+			name:     "issues not created from Discord are ignored",
+			event:    "issues",
+			body:     `{"action": "closed", "sender": {"login": "maintainer"}, "issue": {"number": 8, "user": {"login": "bot"}}, ` + repoJSON + `}`,
+			expected: "",
+		},
+		{
+			name:     "ping",
+			event:    "ping",
+			body:     `{"zen": "Keep it logically awesome."}`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, notifier := newTestHandler(t)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, signedRequest(tt.event, tt.body, testSecret))
+
+			if recorder.Code != http.StatusAccepted {
+				t.Errorf("status = %d, want %d", recorder.Code, http.StatusAccepted)
+			}
+			if tt.expected == "" {
+				if len(notifier.messages) != 0 {
+					t.Errorf("unexpected notification %q", notifier.messages)
+				}
+				return
+			}
+			if len(notifier.messages) != 1 || notifier.messages[0] != tt.expected {
+				t.Errorf("notifications = %q, want %q", notifier.messages, tt.expected)
+			}
+			if notifier.records[0].UserID != "alice" {
+				t.Errorf("notified %q, want the reporter", notifier.records[0].UserID)
+			}
+		})
+	}
+}
+
+func TestHandler_IgnoresRepeatedDelivery(t *testing.T) {
+	handler, notifier := newTestHandler(t)
+
+	body := `{"action": "closed", "sender": {"login": "maintainer"}, "issue": {"number": 7, "user": {"login": "bot"}}, ` + repoJSON + `}`
+	for _, delivery := range []string{"delivery1", "delivery1", "delivery2"} {
+		req := signedRequest("issues", body, testSecret)
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusAccepted {
+			t.Errorf("status = %d, want %d", recorder.Code, http.StatusAccepted)
+		}
+	}
+
+	if len(notifier.messages) != 2 {
+		t.Errorf("notified %d times, want once per distinct delivery", len(notifier.messages))
+	}
+}
+
+func TestHandler_RejectsLargePayload(t *testing.T) {
+	handler, notifier := newTestHandler(t)
+
+	body := `{"action": "closed", "padding": "` + strings.Repeat("x", maxPayloadSize) + `"}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, signedRequest("issues", body, testSecret))
+
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+	if len(notifier.messages) != 0 {
+		t.Errorf("unexpected notification %q", notifier.messages)
+	}
+}
@@ -52,6 +52,8 @@ $DOCKER_CMD run -d \
     --env-file "$ENV_FILE" \
     -e CONFIG_PATH=/app/config.yaml \
     -e FAQ_PATH=/app/faq.yaml \
+    -e DATA_DIR=/app/data \
+    -v meshtastic-bot-data:/app/data \
     -p "${HEALTHCHECK_PORT}:8080" \
     --restart unless-stopped \
     $IMAGE_NAME